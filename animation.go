@@ -0,0 +1,73 @@
+package gol
+
+import (
+	"fmt"
+	"image/gif"
+	"io"
+	"os"
+)
+
+// AnimationOptions configures how a run is exported as an animation
+type AnimationOptions struct {
+	ImageOptions
+	// From and To are the first and last ticks drawn, a To of 0 draws From alone
+	From, To int
+	// Skip is the ticks between frames, defaults to 1
+	Skip int
+	// Delay is the time each frame is shown in hundredths of a second, defaults to 10
+	Delay int
+	// LoopCount is how many times the animation repeats,
+	// 0 repeats forever and -1 plays it once
+	LoopCount int
+}
+
+// EncodeGIF runs a game from tick From to To and writes it as an animated
+// GIF, using the colours of the rules as the palette. The game is advanced
+// to the last frame, so pass a Copy of the game to keep the original
+func EncodeGIF(w io.Writer, g *Game, o AnimationOptions) error {
+	if o.Skip < 1 {
+		o.Skip = 1
+	}
+	if o.Delay < 1 {
+		o.Delay = 10
+	}
+	if o.To < o.From {
+		o.To = o.From
+	}
+	if g.Ticks() > o.From {
+		return fmt.Errorf("game is at tick %d, past the first frame %d", g.Ticks(), o.From)
+	}
+	for g.Ticks() < o.From {
+		g.Tick()
+	}
+
+	im := NewImage(g, o.ImageOptions)
+	anim := &gif.GIF{LoopCount: o.LoopCount}
+	for {
+		anim.Image = append(anim.Image, im.Paletted())
+		anim.Delay = append(anim.Delay, o.Delay)
+		anim.Disposal = append(anim.Disposal, gif.DisposalNone)
+		if g.Ticks()+o.Skip > o.To {
+			break
+		}
+		for step := 0; step < o.Skip; step++ {
+			g.Tick()
+		}
+	}
+	anim.Config.Width, anim.Config.Height = im.Bounds().Dx(), im.Bounds().Dy()
+	anim.Config.ColorModel = anim.Image[0].Palette
+	return gif.EncodeAll(w, anim)
+}
+
+// SaveGIF runs a game and saves it as an animated GIF file
+func SaveGIF(g *Game, Filename string, o AnimationOptions) error {
+	f, err := os.Create(Filename)
+	if err != nil {
+		return err
+	}
+	if err := EncodeGIF(f, g, o); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,56 @@
+package gol
+
+// Animation testing
+
+import (
+	"bytes"
+	"image/gif"
+	"testing"
+)
+
+func TestEncodeGIF(t *testing.T) {
+	g := MakeGame(Options{5, 5, [][]uint8{
+		{0, 0, 0, 0, 0},
+		{0, 0, 1, 0, 0},
+		{0, 0, 1, 0, 0},
+		{0, 0, 1, 0, 0},
+		{0, 0, 0, 0, 0}}, 2, lifeRules()})
+
+	var buf bytes.Buffer
+	o := AnimationOptions{ImageOptions: ImageOptions{CellSize: 3}, From: 1, To: 7, Skip: 2, Delay: 5}
+	c := g.Copy()
+	if err := EncodeGIF(&buf, &c, o); err != nil {
+		t.Fatal(err)
+	}
+	if g.Ticks() != 0 || g.Field.Front[2][2] != 1 || g.Field.Front[2][1] != 0 {
+		t.Fatalf("Encoding a copy changed the original game")
+	}
+	buf.Reset()
+	if err := EncodeGIF(&buf, &g, o); err != nil {
+		t.Fatal(err)
+	}
+	if g.Ticks() != 7 {
+		t.Fatalf("Game not advanced to the last frame, at tick %d", g.Ticks())
+	}
+
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 4 {
+		t.Fatalf("Expected frames at ticks 1, 3, 5 and 7, got %d frames", len(anim.Image))
+	}
+	if anim.Config.Width != 15 || anim.Delay[0] != 5 {
+		t.Fatalf("Scale or delay not applied")
+	}
+
+	// The blinker is horizontal on odd ticks
+	frame := anim.Image[0]
+	if frame.ColorIndexAt(4, 7) != 1 || frame.ColorIndexAt(7, 4) != 0 {
+		t.Fatalf("Frame does not match the game at tick 1")
+	}
+
+	if err := EncodeGIF(&buf, &g, AnimationOptions{From: 2}); err == nil {
+		t.Fatalf("Expected error when the game is past the first frame")
+	}
+}
@@ -0,0 +1,190 @@
+package gol
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// The binary format is the magic and version, the metadata as
+// varints and length prefixed strings, the rules, then the grid
+// row by row with each cell packed into as few bits as the rule
+// count allows and each row padded to a whole byte
+
+const binaryMagic = "GOLB"
+const binaryVersion = 1
+
+// maxBinaryString guards against corrupt length prefixes
+const maxBinaryString = 1 << 20
+
+// cellBits is the bits needed for each cell of a rule count,
+// rounded up so cells never straddle a byte
+func cellBits(RuleAmount int) uint {
+	switch {
+	case RuleAmount <= 2:
+		return 1
+	case RuleAmount <= 4:
+		return 2
+	case RuleAmount <= 16:
+		return 4
+	}
+	return 8
+}
+
+func encodeBinary(w io.Writer, G SaveContent) error {
+	if err := G.validate(); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	var buf [binary.MaxVarintLen64]byte
+	uvarint := func(v uint64) {
+		bw.Write(buf[:binary.PutUvarint(buf[:], v)])
+	}
+	str := func(s string) {
+		uvarint(uint64(len(s)))
+		bw.WriteString(s)
+	}
+
+	bw.WriteString(binaryMagic)
+	bw.WriteByte(binaryVersion)
+	uvarint(uint64(G.X))
+	uvarint(uint64(G.Y))
+	uvarint(uint64(G.Ticks))
+	bw.Write(buf[:binary.PutVarint(buf[:], G.Seed)])
+	str(G.Name)
+	str(G.Description)
+
+	uvarint(uint64(len(G.Rules)))
+	for _, ru := range G.Rules {
+		if ru.Alive {
+			bw.WriteByte(1)
+		} else {
+			bw.WriteByte(0)
+		}
+		bw.Write(ru.Transitions[:])
+		for _, c := range []float32{ru.Colour.R, ru.Colour.G, ru.Colour.B} {
+			binary.LittleEndian.PutUint32(buf[:4], math.Float32bits(c))
+			bw.Write(buf[:4])
+		}
+	}
+
+	bits := cellBits(len(G.Rules))
+	row := make([]byte, (uint(G.X)*bits+7)/8)
+	for y := range G.Grid {
+		for idx := range row {
+			row[idx] = 0
+		}
+		for x, cell := range G.Grid[y] {
+			position := uint(x) * bits
+			row[position/8] |= cell << (position % 8)
+		}
+		if _, err := bw.Write(row); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func decodeBinary(br byteReader) (SaveContent, error) {
+	header := make([]byte, len(binaryMagic)+1)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return SaveContent{}, err
+	}
+	if string(header[:len(binaryMagic)]) != binaryMagic {
+		return SaveContent{}, errors.New("not a binary save")
+	}
+	if header[len(binaryMagic)] != binaryVersion {
+		return SaveContent{}, fmt.Errorf("unsupported binary save version %d", header[len(binaryMagic)])
+	}
+
+	var err error
+	uvarint := func() int {
+		if err != nil {
+			return 0
+		}
+		var v uint64
+		v, err = binary.ReadUvarint(br)
+		if err == nil && v > math.MaxInt32 {
+			err = errors.New("binary save value out of range")
+		}
+		return int(v)
+	}
+	str := func() string {
+		n := uvarint()
+		if err != nil {
+			return ""
+		}
+		if n > maxBinaryString {
+			err = errors.New("binary save string too long")
+			return ""
+		}
+		s := make([]byte, n)
+		_, err = io.ReadFull(br, s)
+		return string(s)
+	}
+
+	G := SaveContent{Version: SaveVersion}
+	G.X = uvarint()
+	G.Y = uvarint()
+	G.Ticks = uvarint()
+	if err == nil {
+		G.Seed, err = binary.ReadVarint(br)
+	}
+	G.Name = str()
+	G.Description = str()
+	ruleAmount := uvarint()
+	if err != nil {
+		return SaveContent{}, err
+	}
+	if ruleAmount == 0 || ruleAmount > 256 {
+		return SaveContent{}, fmt.Errorf("binary save has %d rules", ruleAmount)
+	}
+
+	rule := make([]byte, 1+9+12)
+	G.Rules = make([]Rule, ruleAmount)
+	for idx := range G.Rules {
+		if _, err := io.ReadFull(br, rule); err != nil {
+			return SaveContent{}, err
+		}
+		G.Rules[idx].Alive = rule[0] == 1
+		copy(G.Rules[idx].Transitions[:], rule[1:10])
+		G.Rules[idx].Colour = Colour{
+			math.Float32frombits(binary.LittleEndian.Uint32(rule[10:14])),
+			math.Float32frombits(binary.LittleEndian.Uint32(rule[14:18])),
+			math.Float32frombits(binary.LittleEndian.Uint32(rule[18:22]))}
+	}
+
+	if G.X < 1 || G.Y < 1 {
+		return SaveContent{}, fmt.Errorf("binary save grid of %d by %d cells is empty", G.X, G.Y)
+	}
+	if err := checkGridSize(G.X, G.Y); err != nil {
+		return SaveContent{}, err
+	}
+
+	// The packed cells are read before the grid is made, so a header
+	// claiming more cells than the stream holds is found without
+	// allocating them
+	bits := cellBits(ruleAmount)
+	mask := byte(1<<bits - 1)
+	rowLength := (G.X*int(bits) + 7) / 8
+	var packed bytes.Buffer
+	if _, err := io.CopyN(&packed, br, int64(rowLength*G.Y)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return SaveContent{}, err
+	}
+	G.Grid = MakeGrid(G.X, G.Y)
+	for y := range G.Grid {
+		row := packed.Bytes()[y*rowLength : (y+1)*rowLength]
+		for x := range G.Grid[y] {
+			position := uint(x) * bits
+			G.Grid[y][x] = (row[position/8] >> (position % 8)) & mask
+		}
+	}
+	return G, G.validate()
+}
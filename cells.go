@@ -0,0 +1,207 @@
+package gol
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Plaintext .cells and Life 1.06 are two-state formats, they are
+// read with Conway's rules and written with a cell alive if its rule is
+
+const life106Header = "#Life 1.06"
+
+// LoadCells loads a plaintext .cells file with pad empty cells around it
+func LoadCells(Filename string, pad int) (Options, error) {
+	return loadWith(DecodeCells, Filename, pad)
+}
+
+// SaveCells saves a game to a plaintext .cells file
+func SaveCells(G SaveContent, Filename string) error {
+	return saveWith(EncodeCells, G, Filename)
+}
+
+// LoadLife106 loads a Life 1.06 file with pad empty cells around it
+func LoadLife106(Filename string, pad int) (Options, error) {
+	return loadWith(DecodeLife106, Filename, pad)
+}
+
+// SaveLife106 saves a game to a Life 1.06 file
+func SaveLife106(G SaveContent, Filename string) error {
+	return saveWith(EncodeLife106, G, Filename)
+}
+
+// DecodeCells reads a plaintext pattern of . and O rows,
+// lines starting with ! are comments and !Name: sets the name
+func DecodeCells(r io.Reader) (Pattern, error) {
+	p := Pattern{Rules: lifeRules()}
+	var rows [][]uint8
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if strings.HasPrefix(line, "!") {
+			text := strings.TrimSpace(line[1:])
+			if strings.HasPrefix(text, "Name:") {
+				p.Name = strings.TrimSpace(strings.TrimPrefix(text, "Name:"))
+			} else {
+				p.Comments = append(p.Comments, text)
+			}
+			continue
+		}
+		row := make([]uint8, len(line))
+		for idx, c := range []byte(line) {
+			switch c {
+			case '.':
+			case 'O', '*':
+				row[idx] = 1
+			default:
+				return p, fmt.Errorf("cells line %d contains unknown cell %q", len(rows)+1, c)
+			}
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return p, err
+	}
+	grid, err := patternGrid(rows, 0, 0)
+	if err != nil {
+		return p, err
+	}
+	p.Grid = grid
+	return p, nil
+}
+
+// EncodeCells writes a Pattern as plaintext .cells
+func EncodeCells(w io.Writer, p Pattern) error {
+	bw := bufio.NewWriter(w)
+	if p.Name != "" {
+		fmt.Fprintf(bw, "!Name: %s\n", p.Name)
+	}
+	for _, comment := range p.Comments {
+		fmt.Fprintf(bw, "!%s\n", comment)
+	}
+	for _, row := range p.Grid {
+		end := len(row)
+		for end > 0 && !patternAlive(p, row[end-1]) {
+			end--
+		}
+		for _, cell := range row[:end] {
+			if patternAlive(p, cell) {
+				bw.WriteByte('O')
+			} else {
+				bw.WriteByte('.')
+			}
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// DecodeLife106 reads a Life 1.06 list of alive cell coordinates,
+// the Grid is sized to the bounding box of the cells
+func DecodeLife106(r io.Reader) (Pattern, error) {
+	p := Pattern{Rules: lifeRules()}
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != life106Header {
+		if err := scanner.Err(); err != nil {
+			return p, err
+		}
+		return p, errors.New("Life 1.06 header not found")
+	}
+
+	var xs, ys []int
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			if len(line) < 2 {
+				continue
+			}
+			text := strings.TrimSpace(line[2:])
+			switch line[1] {
+			case 'N':
+				p.Name = text
+			case 'D', 'C':
+				p.Comments = append(p.Comments, text)
+			}
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return p, fmt.Errorf("Life 1.06 line %q is not an x y coordinate", line)
+		}
+		x, errX := strconv.Atoi(fields[0])
+		y, errY := strconv.Atoi(fields[1])
+		if errX != nil || errY != nil {
+			return p, fmt.Errorf("Life 1.06 line %q is not an x y coordinate", line)
+		}
+		xs = append(xs, x)
+		ys = append(ys, y)
+	}
+	if err := scanner.Err(); err != nil {
+		return p, err
+	}
+
+	if len(xs) == 0 {
+		p.Grid = MakeGrid(1, 1)
+		return p, nil
+	}
+	minX, maxX, minY, maxY := xs[0], xs[0], ys[0], ys[0]
+	for idx := range xs {
+		minX, maxX = minInt(minX, xs[idx]), maxInt(maxX, xs[idx])
+		minY, maxY = minInt(minY, ys[idx]), maxInt(maxY, ys[idx])
+	}
+	// The spans are unsigned as they can overflow an int
+	if uint64(maxX)-uint64(minX) >= MaxGridCells || uint64(maxY)-uint64(minY) >= MaxGridCells {
+		return p, fmt.Errorf("Life 1.06 coordinates span over the limit of %d cells", MaxGridCells)
+	}
+	if err := checkGridSize(maxX-minX+1, maxY-minY+1); err != nil {
+		return p, err
+	}
+	p.Grid = MakeGrid(maxX-minX+1, maxY-minY+1)
+	for idx := range xs {
+		p.Grid[ys[idx]-minY][xs[idx]-minX] = 1
+	}
+	return p, nil
+}
+
+// EncodeLife106 writes a Pattern as Life 1.06 coordinates
+func EncodeLife106(w io.Writer, p Pattern) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(life106Header + "\n")
+	for y, row := range p.Grid {
+		for x, cell := range row {
+			if patternAlive(p, cell) {
+				fmt.Fprintf(bw, "%d %d\n", x, y)
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// patternAlive is whether a cell is written as alive in a two-state format
+func patternAlive(p Pattern, cell uint8) bool {
+	if int(cell) < len(p.Rules.Array) {
+		return p.Rules.Array[cell].Alive
+	}
+	return cell != 0
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,100 @@
+package gol
+
+// Plaintext .cells and Life 1.06 testing
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const gliderCells = `!Name: Glider
+!The smallest spaceship
+.O
+..O
+OOO
+`
+
+const gliderLife106 = `#Life 1.06
+0 -1
+1 0
+-1 1
+0 1
+1 1
+`
+
+func TestDecodeCells(t *testing.T) {
+	p, err := DecodeCells(strings.NewReader(gliderCells))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "Glider" || len(p.Comments) != 1 {
+		t.Fatalf("Cells name and comments not read: %q %v", p.Name, p.Comments)
+	}
+
+	arrayOut := [][]uint8{{0, 1, 0}, {0, 0, 1}, {1, 1, 1}}
+	if mismatchCheck(arrayOut, p.Grid) {
+		t.Fatalf("Ragged cells rows not sized to the widest row")
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeCells(&buf, p); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != gliderCells {
+		t.Fatalf("Cells not written back as read:\n%s", buf.String())
+	}
+}
+
+func TestDecodeLife106(t *testing.T) {
+	p, err := DecodeLife106(strings.NewReader(gliderLife106))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	arrayOut := [][]uint8{{0, 1, 0}, {0, 0, 1}, {1, 1, 1}}
+	if mismatchCheck(arrayOut, p.Grid) {
+		t.Fatalf("Negative coordinates not moved into the grid")
+	}
+
+	if _, err := DecodeLife106(strings.NewReader(gliderCells)); err == nil {
+		t.Fatalf("Expected an error without a Life 1.06 header")
+	}
+}
+
+func TestDecodeLife106TooLarge(t *testing.T) {
+	for _, life := range []string{
+		"#Life 1.06\n-2000000000 0\n2000000000 0\n",
+		"#Life 1.06\n0 -9223372036854775808\n0 9223372036854775807\n",
+		"#Life 1.06\n0 0\n10000 10000\n",
+	} {
+		if _, err := DecodeLife106(strings.NewReader(life)); err == nil {
+			t.Fatalf("Expected an error decoding %q", life)
+		}
+	}
+}
+
+func TestEmptyPatternOptions(t *testing.T) {
+	for _, p := range []Pattern{{}, {Grid: [][]uint8{}}} {
+		if o := p.Options(); o.X != 0 || o.Y != 0 {
+			t.Fatalf("Expected an empty pattern to be 0 by 0, got %dx%d", o.X, o.Y)
+		}
+	}
+}
+
+func TestLoadPadded(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "glider.lif")
+	if err := SaveLife106(SaveContent{Rules: rs.Array, Grid: [][]uint8{{0, 1, 0}, {0, 0, 1}, {1, 1, 1}}}, filename); err != nil {
+		t.Fatal(err)
+	}
+
+	o, err := LoadLife106(filename, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := MakeGame(o)
+	if g.X != 9 || g.Y != 9 || g.Field.Front[3][4] != 1 || g.Field.Front[5][3] != 1 {
+		t.Fatalf("Pattern not padded by 3 cells on every side")
+	}
+}
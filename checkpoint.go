@@ -0,0 +1,82 @@
+package gol
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// CheckpointVersion is the current version of the checkpoint format
+const CheckpointVersion = 1
+
+// Checkpoint is the entire state of a Game, resuming from it
+// continues exactly as if the game had never stopped. The alive
+// counts are rebuilt from the field when the game is restored
+type Checkpoint struct {
+	Version   int         `json:"version"`
+	Save      SaveContent `json:"save"`
+	RandState uint64      `json:"randState"`
+}
+
+// Checkpoint captures a copy of the game's entire state
+func (g *Game) Checkpoint() Checkpoint {
+	save := g.SaveContent()
+	save.Rules = append([]Rule(nil), save.Rules...)
+	save.Grid = Options{Grid: save.Grid}.Copy().Grid
+	return Checkpoint{
+		Version:   CheckpointVersion,
+		Save:      save,
+		RandState: g.Rand().State()}
+}
+
+// Game restores a Game from a Checkpoint
+func (c Checkpoint) Game() (Game, error) {
+	if c.Version != CheckpointVersion {
+		return Game{}, fmt.Errorf("unsupported checkpoint version %d", c.Version)
+	}
+	save := c.Save.upgrade()
+	if err := save.validate(); err != nil {
+		return Game{}, err
+	}
+	g := save.Game()
+	g.rand = NewRand(c.RandState)
+	return g, nil
+}
+
+// EncodeCheckpoint writes a game's entire state to a stream
+func EncodeCheckpoint(w io.Writer, g *Game) error {
+	return json.NewEncoder(w).Encode(g.Checkpoint())
+}
+
+// DecodeCheckpoint restores a game from a stream
+func DecodeCheckpoint(r io.Reader) (Game, error) {
+	var c Checkpoint
+	if err := json.NewDecoder(r).Decode(&c); err != nil {
+		return Game{}, err
+	}
+	return c.Game()
+}
+
+// SaveCheckpoint saves a game's entire state to a file
+func SaveCheckpoint(g *Game, Filename string) error {
+	f, err := os.Create(Filename)
+	if err != nil {
+		return err
+	}
+	if err := EncodeCheckpoint(f, g); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadCheckpoint restores a game from a file
+func LoadCheckpoint(Filename string) (Game, error) {
+	f, err := os.Open(Filename)
+	if err != nil {
+		return Game{}, err
+	}
+	defer f.Close()
+	return DecodeCheckpoint(f)
+}
@@ -0,0 +1,102 @@
+package gol
+
+// Checkpoint testing
+
+// Checking that a resumed game is identical to one that never stopped
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// continueRun ticks and resets a game so that both the
+// field and the random generator are exercised
+func continueRun(g *Game) {
+	for i := 0; i < 15; i++ {
+		g.Tick()
+	}
+	g.Reset()
+	for i := 0; i < 15; i++ {
+		g.Tick()
+	}
+	g.Reset()
+	g.Tick()
+}
+
+func TestCheckpointResume(t *testing.T) {
+	copyOpts = opts
+	copyOpts.X = 30
+	copyOpts.Y = 20
+	copyOpts.RuleNumber = 4
+	g := MakeGame(copyOpts)
+	g.Seed(99)
+	for i := 0; i < 10; i++ {
+		g.Tick()
+	}
+	g.Reset()
+	g.Tick()
+
+	filename := filepath.Join(t.TempDir(), "checkpoint.json")
+	if err := SaveCheckpoint(&g, filename); err != nil {
+		t.Fatal(err)
+	}
+
+	continueRun(&g)
+
+	resumed, err := LoadCheckpoint(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed.Ticks() != 1 {
+		t.Fatalf("Tick counter not resumed, got %d", resumed.Ticks())
+	}
+	continueRun(&resumed)
+
+	if g.Ticks() != resumed.Ticks() {
+		t.Fatalf("Tick counters differ, %d and %d", g.Ticks(), resumed.Ticks())
+	}
+	if mismatchCheck(g.Field.Front, resumed.Field.Front) {
+		t.Fatalf("Resumed field differs from uninterrupted run")
+	}
+	if mismatchCheck(g.aliveCount.Front, resumed.aliveCount.Front) {
+		t.Fatalf("Resumed alive counts differ from uninterrupted run")
+	}
+	if g.Rand().State() != resumed.Rand().State() {
+		t.Fatalf("Resumed random state differs from uninterrupted run")
+	}
+}
+
+func TestCheckpointIsACopy(t *testing.T) {
+	g := MakeGame(Options{5, 5, [][]uint8{}, 2, rs})
+	c := g.Checkpoint()
+	c.Save.Grid[0][0] = 1 - c.Save.Grid[0][0]
+
+	if c.Save.Grid[0][0] == g.Field.Front[0][0] {
+		t.Fatalf("Checkpoint shares the game's field")
+	}
+}
+
+func TestGameCopy(t *testing.T) {
+	g := MakeGame(Options{8, 8, [][]uint8{}, 3, Rules{}})
+	g.Seed(11)
+	g.Tick()
+	c := g.Copy()
+	before := copyGrid(g.Field.Front)
+
+	c.Tick()
+	c.Tick()
+	c.Rules.Array[0].Colour = Colour{1, 1, 1}
+	if mismatchCheck(before, g.Field.Front) || g.Ticks() != 1 || g.Rules.Array[0].Colour == c.Rules.Array[0].Colour {
+		t.Fatalf("Ticking a copy changed the original")
+	}
+
+	// Both carry on identically from the same random state
+	c = g.Copy()
+	g.Reset()
+	c.Reset()
+	g.Tick()
+	c.Tick()
+	if mismatchCheck(g.Field.Front, c.Field.Front) || mismatchCheck(g.aliveCount.Front, c.aliveCount.Front) {
+		t.Fatalf("Copy does not continue like the original")
+	}
+}
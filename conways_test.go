@@ -96,15 +96,21 @@ func TestConwayGlider(t *testing.T) {
 
 // TestLoad - Loading a file
 func TestLoad(t *testing.T) {
-	Options := Load("glider.json")
+	Options, err := Load("glider.json")
+	if err != nil {
+		t.Fatal(err)
+	}
 	g := MakeGame(Options)
 
 	for idx, r := range g.Rules.Array {
-		if rs.Array[idx] != r {
+		if rs.Array[idx].Alive != r.Alive || rs.Array[idx].Transitions != r.Transitions {
 			fmt.Println(rs.Array[idx])
 			fmt.Println(r)
 			t.Fatalf("Rules do not match")
 		}
+		if r.Colour != DefaultColour(idx, len(g.Rules.Array)) {
+			t.Fatalf("Missing colours not set to defaults")
+		}
 	}
 
 	y0Out := []uint8{0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
@@ -0,0 +1,116 @@
+package gol
+
+import "fmt"
+
+// Edits change cells between ticks, keeping the neighbour counts the
+// next Tick uses consistent. They must not be called during a Tick.
+// Each edit that changes cells calls the observers once with them
+
+// Cell returns the rule of a cell
+func (g *Game) Cell(x, y int) uint8 {
+	return g.Field.Front[y][x]
+}
+
+// Set changes the rule of a cell
+func (g *Game) Set(x, y int, rule uint8) error {
+	if x < 0 || y < 0 || x >= g.X || y >= g.Y {
+		return fmt.Errorf("cell X: %d Y: %d outside %dx%d game", x, y, g.X, g.Y)
+	}
+	if int(rule) >= len(g.Rules.Array) {
+		return fmt.Errorf("rule %d of %d", rule, len(g.Rules.Array))
+	}
+	g.changes = g.changes[:0]
+	if g.set(x, y, rule, g.recordChanges()) {
+		g.notify()
+	}
+	return nil
+}
+
+// set changes the rule of a cell inside the game, adding it to the
+// changes if record is true. It is false if the rule is unchanged
+func (g *Game) set(x, y int, rule uint8, record bool) bool {
+	old := g.Field.Front[y][x]
+	if old == rule {
+		return false
+	}
+	g.Field.Front[y][x] = rule
+	alive := g.Rules.Array[rule].Alive
+	if alive != g.alives.array[y][x] {
+		g.updateAliveCounts(g.aliveCount.Front, x, y, alive)
+		g.alives.array[y][x] = alive
+	}
+	if record {
+		g.changes = append(g.changes, CellChange{x, y, old, rule})
+	}
+	return true
+}
+
+// Fill sets every cell in a rectangle to a rule, the
+// rectangle is clipped to the game
+func (g *Game) Fill(x, y, width, height int, rule uint8) error {
+	if int(rule) >= len(g.Rules.Array) {
+		return fmt.Errorf("rule %d of %d", rule, len(g.Rules.Array))
+	}
+	x0, y0, x1, y1 := g.clip(x, y, width, height)
+	record, changed := g.recordChanges(), false
+	g.changes = g.changes[:0]
+	for cy := y0; cy < y1; cy++ {
+		for cx := x0; cx < x1; cx++ {
+			changed = g.set(cx, cy, rule, record) || changed
+		}
+	}
+	if changed {
+		g.notify()
+	}
+	return nil
+}
+
+// Region copies the rules of a rectangle of cells, the
+// rectangle is clipped to the game
+func (g *Game) Region(x, y, width, height int) [][]uint8 {
+	x0, y0, x1, y1 := g.clip(x, y, width, height)
+	region := MakeGrid(x1-x0, y1-y0)
+	for cy := range region {
+		copy(region[cy], g.Field.Front[y0+cy][x0:x1])
+	}
+	return region
+}
+
+// Paste sets the cells of a grid with its first cell at x, y,
+// cells falling outside the game are skipped
+func (g *Game) Paste(x, y int, grid [][]uint8) error {
+	for gy := range grid {
+		for gx, rule := range grid[gy] {
+			if int(rule) >= len(g.Rules.Array) {
+				return fmt.Errorf("pasted cell X: %d Y: %d uses rule %d of %d", gx, gy, rule, len(g.Rules.Array))
+			}
+		}
+	}
+	record, changed := g.recordChanges(), false
+	g.changes = g.changes[:0]
+	for gy := range grid {
+		for gx, rule := range grid[gy] {
+			cx, cy := x+gx, y+gy
+			if cx >= 0 && cy >= 0 && cx < g.X && cy < g.Y {
+				changed = g.set(cx, cy, rule, record) || changed
+			}
+		}
+	}
+	if changed {
+		g.notify()
+	}
+	return nil
+}
+
+// clip a rectangle to the game, returning its corners
+func (g *Game) clip(x, y, width, height int) (x0, y0, x1, y1 int) {
+	x0, y0 = maxInt(x, 0), maxInt(y, 0)
+	x1, y1 = minInt(x+width, g.X), minInt(y+height, g.Y)
+	if x1 < x0 {
+		x1 = x0
+	}
+	if y1 < y0 {
+		y1 = y0
+	}
+	return
+}
@@ -0,0 +1,82 @@
+package gol
+
+// Cell edit testing
+
+import "testing"
+
+func TestSetKeepsCountsConsistent(t *testing.T) {
+	g := MakeGame(Options{12, 10, [][]uint8{}, 3, Rules{}})
+	g.Tick()
+
+	// Edit the game, then compare its next tick with a game made from the edited field
+	g.Set(1, 1, 1)
+	g.Set(0, 0, 0)
+	g.Fill(2, 0, 2, 2, 1)
+	edited := MakeGame(Options{g.X, g.Y, copyGrid(g.Field.Front), 0, g.Rules})
+
+	g.Tick()
+	edited.Tick()
+	if mismatchCheck(edited.Field.Front, g.Field.Front) {
+		t.Fatalf("Edited game did not tick like a game made from its field")
+	}
+	if g.Population() != edited.Population() {
+		t.Fatalf("Expected population %d, got %d", edited.Population(), g.Population())
+	}
+
+	if err := g.Set(g.X, 0, 0); err == nil {
+		t.Fatalf("Expected error setting a cell outside the game")
+	}
+	if err := g.Set(0, 0, uint8(len(g.Rules.Array))); err == nil {
+		t.Fatalf("Expected error setting a rule that does not exist")
+	}
+}
+
+func TestRegionPaste(t *testing.T) {
+	g := MakeGame(Options{4, 3, [][]uint8{{1, 0, 0, 0}, {0, 1, 0, 0}, {1, 1, 1, 0}}, 2, lifeRules()})
+
+	region := g.Region(-1, 0, 3, 5)
+	if mismatchCheck([][]uint8{{1, 0}, {0, 1}, {1, 1}}, region) {
+		t.Fatalf("Region not clipped to the game")
+	}
+
+	g.Paste(3, 1, region)
+	if mismatchCheck([][]uint8{{1, 0, 0, 0}, {0, 1, 0, 1}, {1, 1, 1, 0}}, g.Field.Front) {
+		t.Fatalf("Paste not clipped to the game")
+	}
+	if g.Population() != 6 {
+		t.Fatalf("Expected population 6 after paste, got %d", g.Population())
+	}
+
+	if err := g.Paste(0, 0, [][]uint8{{2}}); err == nil {
+		t.Fatalf("Expected error pasting a rule that does not exist")
+	}
+}
+
+func TestEditsObserved(t *testing.T) {
+	g := MakeGame(Options{4, 3, [][]uint8{{1, 0, 0, 0}, {0, 1, 0, 0}, {1, 1, 1, 0}}, 2, lifeRules()})
+	var ticks, calls []int
+	var changes []CellChange
+	g.AddObserver(func(g *Game, tick int, c []CellChange) {
+		ticks = append(ticks, tick)
+		changes = append(changes[:0], c...)
+	}, true)
+	g.AddObserver(func(g *Game, tick int, c []CellChange) {
+		calls = append(calls, len(c))
+	}, false)
+
+	// A fill is observed once at the current tick with only the cells it changed
+	g.Fill(0, 0, 2, 1, 1)
+	if len(ticks) != 1 || ticks[0] != 0 || len(changes) != 1 || changes[0] != (CellChange{1, 0, 0, 1}) {
+		t.Fatalf("Expected one call at tick 0 changing cell 1, 0, got ticks %v and %v", ticks, changes)
+	}
+	if len(calls) != 1 || calls[0] != 0 {
+		t.Fatalf("Expected no changes for an observer that did not ask for them, got %v", calls)
+	}
+
+	// Edits that change nothing are not observed
+	g.Set(0, 0, 1)
+	g.Paste(3, 2, [][]uint8{{0, 1}})
+	if len(ticks) != 1 {
+		t.Fatalf("Expected unchanged cells not to be observed, got ticks %v", ticks)
+	}
+}
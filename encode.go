@@ -0,0 +1,319 @@
+package gol
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Format is a way of writing a saved game
+type Format int
+
+// Formats supported by Encode and Decode
+const (
+	FormatJSON Format = iota
+	FormatBinary
+	FormatRLE
+	FormatCells
+	FormatLife106
+)
+
+// Compression applied around an encoded Format
+type Compression int
+
+// Compressions supported by Encode, Decode detects them automatically
+const (
+	CompressionNone Compression = iota
+	CompressionGzip
+)
+
+// EncodeOptions chooses how Encode writes a game
+type EncodeOptions struct {
+	Format      Format
+	Compression Compression
+}
+
+// FormatFromFilename picks EncodeOptions from a file extension, such as
+// .json, .golb, .rle, .cells or .lif, optionally followed by .gz
+func FormatFromFilename(Filename string) EncodeOptions {
+	o := EncodeOptions{}
+	ext := strings.ToLower(filepath.Ext(Filename))
+	if ext == ".gz" {
+		o.Compression = CompressionGzip
+		ext = strings.ToLower(filepath.Ext(strings.TrimSuffix(Filename, filepath.Ext(Filename))))
+	}
+	switch ext {
+	case ".golb":
+		o.Format = FormatBinary
+	case ".rle":
+		o.Format = FormatRLE
+	case ".cells":
+		o.Format = FormatCells
+	case ".lif", ".life":
+		o.Format = FormatLife106
+	}
+	return o
+}
+
+// SaveFile saves a game in the format chosen by the file extension
+func SaveFile(G SaveContent, Filename string) error {
+	f, err := os.Create(Filename)
+	if err != nil {
+		return err
+	}
+	if err := Encode(f, G, FormatFromFilename(Filename)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadFile loads a game of any format Decode supports
+func LoadFile(Filename string) (SaveContent, error) {
+	f, err := os.Open(Filename)
+	if err != nil {
+		return SaveContent{}, err
+	}
+	defer f.Close()
+	return Decode(f)
+}
+
+// Encode writes a game to a stream. Pattern formats keep the name
+// and description but not the other metadata
+func Encode(w io.Writer, G SaveContent, o EncodeOptions) error {
+	G = G.upgrade()
+	var gz *gzip.Writer
+	if o.Compression == CompressionGzip {
+		gz = gzip.NewWriter(w)
+		w = gz
+	}
+
+	var err error
+	switch o.Format {
+	case FormatJSON:
+		err = json.NewEncoder(w).Encode(G)
+	case FormatBinary:
+		err = encodeBinary(w, G)
+	case FormatRLE:
+		err = EncodeRLE(w, contentPattern(G))
+	case FormatCells:
+		err = EncodeCells(w, contentPattern(G))
+	case FormatLife106:
+		err = EncodeLife106(w, contentPattern(G))
+	default:
+		err = errors.New("unknown format")
+	}
+
+	if gz != nil {
+		if closeErr := gz.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// Decode reads a game from a stream, detecting gzip compression and
+// the format from the content. When r is an io.ByteReader, such as a
+// *bufio.Reader or *bytes.Buffer, nothing after a JSON or binary save
+// is read, so several saves can be decoded one after another from the
+// same stream. Pattern formats are read to the end of the stream
+func Decode(r io.Reader) (SaveContent, error) {
+	sr := newSaveReader(r)
+	magic, _ := sr.Peek(2)
+	if !bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		return decodeStream(sr)
+	}
+
+	gz, err := gzip.NewReader(sr)
+	if err != nil {
+		return SaveContent{}, err
+	}
+	defer gz.Close()
+	// Each save is its own gzip member, so a following save is left alone
+	gz.Multistream(false)
+	G, err := decodeStream(newSaveReader(gz))
+	if err != nil {
+		return SaveContent{}, err
+	}
+	// Reading to the end of the member checks its trailer and consumes it
+	if _, err := io.Copy(ioutil.Discard, gz); err != nil {
+		return SaveContent{}, err
+	}
+	return G, nil
+}
+
+// decodeStream reads a game of any format from an uncompressed stream
+func decodeStream(br *saveReader) (SaveContent, error) {
+	format, err := detectFormat(br)
+	if err != nil {
+		return SaveContent{}, err
+	}
+
+	var p Pattern
+	switch format {
+	case FormatJSON:
+		return decodeJSON(br)
+	case FormatBinary:
+		return decodeBinary(br)
+	case FormatRLE:
+		p, err = DecodeRLE(br)
+	case FormatCells:
+		p, err = DecodeCells(br)
+	case FormatLife106:
+		p, err = DecodeLife106(br)
+	}
+	if err != nil {
+		return SaveContent{}, err
+	}
+	return patternContent(p), nil
+}
+
+// byteReader is a stream that can be read a byte at a time,
+// so a decoder can stop exactly at the end of a save
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// saveReader reads a save without reading ahead of what is
+// decoded, with a Peek for detecting its format
+type saveReader struct {
+	r      byteReader
+	peeked []byte
+}
+
+// newSaveReader reads from r directly if it is a byteReader,
+// otherwise through a buffer that may read past the save
+func newSaveReader(r io.Reader) *saveReader {
+	if sr, ok := r.(*saveReader); ok {
+		return sr
+	}
+	if br, ok := r.(byteReader); ok {
+		return &saveReader{r: br}
+	}
+	return &saveReader{r: bufio.NewReader(r)}
+}
+
+// Peek returns the next n bytes without consuming them
+func (sr *saveReader) Peek(n int) ([]byte, error) {
+	for len(sr.peeked) < n {
+		b, err := sr.r.ReadByte()
+		if err != nil {
+			return sr.peeked, err
+		}
+		sr.peeked = append(sr.peeked, b)
+	}
+	return sr.peeked[:n], nil
+}
+
+// ReadByte reads a single byte
+func (sr *saveReader) ReadByte() (byte, error) {
+	if len(sr.peeked) > 0 {
+		b := sr.peeked[0]
+		sr.peeked = sr.peeked[1:]
+		return b, nil
+	}
+	return sr.r.ReadByte()
+}
+
+// Read reads the peeked bytes, then reads from the stream
+func (sr *saveReader) Read(p []byte) (int, error) {
+	if len(sr.peeked) > 0 {
+		n := copy(p, sr.peeked)
+		sr.peeked = sr.peeked[n:]
+		return n, nil
+	}
+	return sr.r.Read(p)
+}
+
+// readJSONObject reads a JSON object a byte at a time,
+// stopping at the brace that closes it
+func readJSONObject(r io.ByteReader) ([]byte, error) {
+	var data []byte
+	depth := 0
+	inString, escaped := false, false
+	for {
+		b, err := r.ReadByte()
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b)
+		switch {
+		case inString:
+			if escaped {
+				escaped = false
+			} else if b == '\\' {
+				escaped = true
+			} else if b == '"' {
+				inString = false
+			}
+		case b == '"':
+			inString = true
+		case b == '{' || b == '[':
+			depth++
+		case b == '}' || b == ']':
+			depth--
+			if depth == 0 {
+				return data, nil
+			}
+		case depth == 0 && b != ' ' && b != '\t' && b != '\r' && b != '\n':
+			return nil, errors.New("save is not a JSON object")
+		}
+	}
+}
+
+// detectFormat peeks at the start of a stream to find its Format
+func detectFormat(br *saveReader) (Format, error) {
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return 0, errors.New("empty save")
+		}
+		if b[0] != ' ' && b[0] != '\t' && b[0] != '\r' && b[0] != '\n' {
+			break
+		}
+		br.ReadByte()
+	}
+	start, _ := br.Peek(len(life106Header))
+	switch {
+	case bytes.HasPrefix(start, []byte(binaryMagic)):
+		return FormatBinary, nil
+	case start[0] == '{':
+		return FormatJSON, nil
+	case bytes.HasPrefix(start, []byte(life106Header)):
+		return FormatLife106, nil
+	case start[0] == '!' || start[0] == '.' || start[0] == 'O' || start[0] == '*':
+		return FormatCells, nil
+	case start[0] == '#' || start[0] == 'x':
+		return FormatRLE, nil
+	}
+	return 0, errors.New("unknown save format")
+}
+
+// contentPattern converts a saved game for the pattern encoders
+func contentPattern(G SaveContent) Pattern {
+	p := Pattern{Name: G.Name, Grid: G.Grid, Rules: Rules{G.Rules}}
+	if G.Description != "" {
+		p.Comments = strings.Split(G.Description, "\n")
+	}
+	return p
+}
+
+// patternContent converts a decoded pattern to a saved game
+func patternContent(p Pattern) SaveContent {
+	return SaveContent{
+		Name:        p.Name,
+		Description: strings.Join(p.Comments, "\n"),
+		Rules:       p.Rules.Array,
+		Grid:        p.Grid}.upgrade()
+}
@@ -0,0 +1,144 @@
+package gol
+
+// Streaming encode and decode testing
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestEncodeDecodeFormats(t *testing.T) {
+	grid := MakeGrid(12, 9)
+	grid[2][3], grid[3][4], grid[4][2], grid[4][3], grid[4][4] = 1, 1, 1, 1, 1
+	content := SaveContent{Name: "Glider", Description: "A\nB", Rules: rs.Array, Grid: grid}
+
+	for _, format := range []Format{FormatJSON, FormatBinary, FormatRLE, FormatCells, FormatLife106} {
+		for _, compression := range []Compression{CompressionNone, CompressionGzip} {
+			var buf bytes.Buffer
+			if err := Encode(&buf, content, EncodeOptions{format, compression}); err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := Decode(&buf)
+			if err != nil {
+				t.Fatalf("Format %d compression %d: %v", format, compression, err)
+			}
+
+			expected := grid
+			if format == FormatCells {
+				// Trailing dead cells are not written
+				expected = [][]uint8{{0, 1, 0}, {0, 0, 1}, {1, 1, 1}}
+				decoded.Grid = decoded.Grid[2:]
+				for idx := range decoded.Grid {
+					decoded.Grid[idx] = decoded.Grid[idx][2:]
+				}
+			} else if format == FormatLife106 {
+				// Coordinates are read back as their bounding box
+				expected = [][]uint8{{0, 1, 0}, {0, 0, 1}, {1, 1, 1}}
+			}
+			if mismatchCheck(expected, decoded.Grid) {
+				t.Fatalf("Format %d compression %d did not keep the grid", format, compression)
+			}
+			if format != FormatLife106 && decoded.Name != "Glider" {
+				t.Fatalf("Format %d compression %d did not keep the name", format, compression)
+			}
+		}
+	}
+}
+
+func TestBinaryPacking(t *testing.T) {
+	for _, ruleAmount := range []int{2, 3, 5, 17, 200} {
+		copyOpts = opts
+		copyOpts.X = 13
+		copyOpts.Y = 7
+		copyOpts.RuleNumber = ruleAmount
+		g := MakeGame(copyOpts)
+		for i := 0; i < 3; i++ {
+			g.Tick()
+		}
+		content := g.SaveContent()
+		content.Seed = -42
+
+		var buf bytes.Buffer
+		if err := Encode(&buf, content, EncodeOptions{Format: FormatBinary}); err != nil {
+			t.Fatal(err)
+		}
+		if ruleAmount == 2 && buf.Len() > 100 {
+			t.Fatalf("Two rule grid not bit packed, %d bytes", buf.Len())
+		}
+
+		decoded, err := Decode(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded.Ticks != 3 || decoded.Seed != -42 || decoded.X != 13 || decoded.Y != 7 {
+			t.Fatalf("Binary metadata not kept: %+v", decoded)
+		}
+		for idx, r := range decoded.Rules {
+			if r != g.Rules.Array[idx] {
+				t.Fatalf("Binary rule %d not kept", idx)
+			}
+		}
+		if mismatchCheck(g.Field.Front, decoded.Grid) {
+			t.Fatalf("%d rule grid not kept", ruleAmount)
+		}
+	}
+}
+
+func TestFormatFromFilename(t *testing.T) {
+	o := FormatFromFilename("field.golb.gz")
+	if o.Format != FormatBinary || o.Compression != CompressionGzip {
+		t.Fatalf("Compressed binary not detected from extension")
+	}
+	if FormatFromFilename("glider.rle").Format != FormatRLE || FormatFromFilename("x.json").Format != FormatJSON {
+		t.Fatalf("Formats not detected from extension")
+	}
+}
+
+func TestDecodeStream(t *testing.T) {
+	first := SaveContent{Name: "First", Rules: rs.Array, Grid: [][]uint8{{0, 1}, {1, 0}}}
+	second := SaveContent{Name: "Second", Rules: rs.Array, Grid: [][]uint8{{1, 1, 1}}}
+
+	for _, format := range []Format{FormatJSON, FormatBinary} {
+		for _, compression := range []Compression{CompressionNone, CompressionGzip} {
+			var buf bytes.Buffer
+			for _, content := range []SaveContent{first, second} {
+				if err := Encode(&buf, content, EncodeOptions{format, compression}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for _, expected := range []SaveContent{first, second} {
+				decoded, err := Decode(&buf)
+				if err != nil {
+					t.Fatalf("Format %d compression %d: %v", format, compression, err)
+				}
+				if decoded.Name != expected.Name || mismatchCheck(expected.Grid, decoded.Grid) {
+					t.Fatalf("Format %d compression %d: expected %s, got %s", format, compression, expected.Name, decoded.Name)
+				}
+			}
+			if strings.TrimSpace(buf.String()) != "" {
+				t.Fatalf("Format %d compression %d left %d bytes", format, compression, buf.Len())
+			}
+		}
+	}
+}
+
+func TestDecodeBinaryTooLarge(t *testing.T) {
+	header := func(x, y uint64) []byte {
+		b := append([]byte(binaryMagic), binaryVersion)
+		b = binary.AppendUvarint(b, x)
+		b = binary.AppendUvarint(b, y)
+		b = binary.AppendUvarint(b, 0)
+		b = binary.AppendVarint(b, 0)
+		b = append(b, 0, 0, 1)
+		return append(b, make([]byte, 22)...)
+	}
+
+	for _, size := range [][2]uint64{{1<<31 - 1, 1<<31 - 1}, {1 << 13, 1 << 13}, {0, 1 << 30}} {
+		if _, err := Decode(bytes.NewReader(header(size[0], size[1]))); err == nil {
+			t.Fatalf("Expected an error for a %d by %d grid with no cells", size[0], size[1])
+		}
+	}
+}
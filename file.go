@@ -2,36 +2,174 @@ package gol
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"io/ioutil"
 )
 
+// SaveVersion is the current version of the save format,
+// files without a version are version 0 and only have rules and a grid
+const SaveVersion = 1
+
 // SaveContent used with the save function to write to a file
 type SaveContent struct {
-	Rules []Rule    `json:"rules"`
-	Grid  [][]uint8 `json:"grid"`
+	Version int `json:"version"`
+	X       int `json:"x"`
+	Y       int `json:"y"`
+	Ticks   int `json:"ticks"`
+	// Seed is the state of the game's random generator, so a
+	// loaded game repeats the same Resets. 0 leaves it unseeded
+	Seed        int64     `json:"seed,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Rules       []Rule    `json:"rules"`
+	Grid        [][]uint8 `json:"grid"`
+}
+
+// savedRule lets loading tell a missing colour from black
+type savedRule struct {
+	Alive       bool
+	Transitions [9]uint8
+	Colour      *Colour
+}
+
+// SaveContent captures a game's current state for saving
+func (g *Game) SaveContent() SaveContent {
+	return SaveContent{
+		Version: SaveVersion,
+		X:       g.X,
+		Y:       g.Y,
+		Ticks:   g.ticks,
+		Seed:    int64(g.Rand().State()),
+		Rules:   g.Rules.Array,
+		Grid:    g.Field.Front}
+}
+
+// Options to make a Game of the saved state
+func (s SaveContent) Options() Options {
+	return Options{
+		X:          s.X,
+		Y:          s.Y,
+		Grid:       s.Grid,
+		RuleNumber: 0,
+		Rules:      Rules{s.Rules}}
+}
+
+// Game makes a Game of the saved state, with its tick counter
+// and random generator restored
+func (s SaveContent) Game() Game {
+	g := MakeGame(s.Options())
+	g.ticks = s.Ticks
+	if s.Seed != 0 {
+		g.Seed(uint64(s.Seed))
+	}
+	return g
 }
 
 // Save game of life to a file
-func Save(G SaveContent, Filename string) {
-	json, err := json.Marshal(G)
+func Save(G SaveContent, Filename string) error {
+	json, err := json.Marshal(G.upgrade())
 	if err != nil {
-		panic(err)
+		return err
 	}
-	ioutil.WriteFile(Filename, json, 0644)
+	return ioutil.WriteFile(Filename, json, 0644)
 }
 
 // Load a game from file
-func Load(Filename string) Options {
+func Load(Filename string) (Options, error) {
+	gs, err := LoadContent(Filename)
+	if err != nil {
+		return Options{}, err
+	}
+	return gs.Options(), nil
+}
+
+// LoadContent loads a saved game with its metadata, upgrading
+// older versions of the save format to the current one
+func LoadContent(Filename string) (SaveContent, error) {
 	data, err := ioutil.ReadFile(Filename)
 	if err != nil {
-		panic(err)
+		return SaveContent{}, err
 	}
-	gs := SaveContent{}
-	json.Unmarshal(data, &gs)
-	return Options{
-		X:          0,
-		Y:          0,
-		Grid:       gs.Grid,
-		RuleNumber: 0,
-		Rules:      Rules{gs.Rules}}
+	return decodeContent(data)
+}
+
+// decodeJSON reads a single saved game from a stream,
+// reading nothing after the end of its JSON object
+func decodeJSON(r io.ByteReader) (SaveContent, error) {
+	data, err := readJSONObject(r)
+	if err != nil {
+		return SaveContent{}, err
+	}
+	return decodeContent(data)
+}
+
+// decodeContent reads a saved game from its JSON
+func decodeContent(data []byte) (SaveContent, error) {
+	var saved struct {
+		SaveContent
+		Rules []savedRule `json:"rules"`
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return SaveContent{}, err
+	}
+	gs := saved.SaveContent
+	if gs.Version > SaveVersion {
+		return SaveContent{}, fmt.Errorf("save version %d is newer than supported version %d", gs.Version, SaveVersion)
+	}
+	gs.Rules = make([]Rule, len(saved.Rules))
+	for idx, ru := range saved.Rules {
+		gs.Rules[idx] = Rule{Alive: ru.Alive, Transitions: ru.Transitions}
+		if ru.Colour == nil {
+			gs.Rules[idx].Colour = DefaultColour(idx, len(saved.Rules))
+		} else {
+			gs.Rules[idx].Colour = *ru.Colour
+		}
+	}
+	gs = gs.upgrade()
+	return gs, gs.validate()
+}
+
+// upgrade fills in metadata missing from older versions
+func (s SaveContent) upgrade() SaveContent {
+	s.Version = SaveVersion
+	if s.Y == 0 {
+		s.Y = len(s.Grid)
+	}
+	if s.X == 0 && len(s.Grid) > 0 {
+		s.X = len(s.Grid[0])
+	}
+	return s
+}
+
+// validate that the saved grid matches its dimensions and rules
+func (s SaveContent) validate() error {
+	if len(s.Rules) == 0 {
+		return fmt.Errorf("save has no rules")
+	}
+	for idx, ru := range s.Rules {
+		for count, next := range ru.Transitions {
+			if int(next) >= len(s.Rules) {
+				return fmt.Errorf("save rule %d goes to rule %d with %d neighbours but there are %d rules",
+					idx, next, count, len(s.Rules))
+			}
+		}
+	}
+	if len(s.Grid) != s.Y {
+		return fmt.Errorf("save grid has %d rows but y is %d", len(s.Grid), s.Y)
+	}
+	for y := range s.Grid {
+		if len(s.Grid[y]) != s.X {
+			return fmt.Errorf("save grid row %d has %d cells but x is %d", y, len(s.Grid[y]), s.X)
+		}
+		for x, cell := range s.Grid[y] {
+			if int(cell) >= len(s.Rules) {
+				return fmt.Errorf("save cell X: %d Y: %d uses rule %d of %d", x, y, cell, len(s.Rules))
+			}
+		}
+	}
+	if s.Ticks < 0 {
+		return fmt.Errorf("save ticks cannot be negative")
+	}
+	return nil
 }
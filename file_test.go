@@ -0,0 +1,85 @@
+package gol
+
+// Save format testing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveRoundTrip(t *testing.T) {
+	g := MakeGame(Options{10, 10, [][]uint8{}, 3, Rules{}})
+	for i := 0; i < 7; i++ {
+		g.Tick()
+	}
+
+	content := g.SaveContent()
+	content.Name = "Round trip"
+	filename := filepath.Join(t.TempDir(), "save.json")
+	if err := Save(content, filename); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadContent(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Version != SaveVersion || loaded.X != 10 || loaded.Y != 10 || loaded.Name != "Round trip" {
+		t.Fatalf("Metadata not saved: %+v", loaded)
+	}
+
+	restored := loaded.Game()
+	if restored.Ticks() != 7 {
+		t.Fatalf("Tick counter not restored, got %d", restored.Ticks())
+	}
+	for idx, r := range restored.Rules.Array {
+		if r != g.Rules.Array[idx] {
+			t.Fatalf("Rule %d not restored with its colour", idx)
+		}
+	}
+	if mismatchCheck(g.Field.Front, restored.Field.Front) {
+		t.Fatalf("Grid not restored")
+	}
+
+	g.Reset()
+	restored.Reset()
+	if mismatchCheck(g.Field.Front, restored.Field.Front) {
+		t.Fatalf("Random generator not restored, resets differ")
+	}
+}
+
+func TestLoadUpgrade(t *testing.T) {
+	loaded, err := LoadContent("glider.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Version != SaveVersion || loaded.X != 10 || loaded.Y != 10 || loaded.Ticks != 0 {
+		t.Fatalf("Version 0 save not upgraded: %+v", loaded)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := Load(filepath.Join(dir, "missing.json")); err == nil {
+		t.Fatalf("Expected an error loading a missing file")
+	}
+
+	cases := map[string]string{
+		"invalid.json": `{"rules": [`,
+		"newer.json":   `{"version": 99, "rules": [{"Alive": true}], "grid": ["AA=="]}`,
+		"rules.json":   `{"rules": [{"Alive": true}], "grid": ["AAU="]}`,
+		"ragged.json":  `{"rules": [{"Alive": true}], "grid": ["AAA=", "AA=="]}`,
+		"next.json":    `{"rules": [{"Transitions": [0, 0, 0, 200, 0, 0, 0, 0, 0]}], "grid": ["AA=="]}`,
+	}
+	for name, data := range cases {
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(filename); err == nil {
+			t.Fatalf("Expected an error loading %s", name)
+		}
+	}
+}
@@ -10,6 +10,49 @@ type Game struct {
 	alives     alives
 	aliveCount GridBuffers
 	ticks      int
+	resets     int
+	rand       *Rand
+	observers  []*observer
+	changes    []CellChange
+}
+
+// CellChange is a cell that changed rule during a Tick or an edit
+type CellChange struct {
+	X, Y     int
+	Old, New uint8
+}
+
+// Observer is called after every Tick with the new tick count, and
+// after every edit that changes cells with the tick count unchanged.
+// changes is only filled for observers that asked for them and
+// is reused after the call returns
+type Observer func(g *Game, tick int, changes []CellChange)
+
+type observer struct {
+	function Observer
+	changes  bool
+}
+
+// AddObserver registers an Observer to be called after every Tick and edit,
+// if changes is true the Observer receives every cell that changed rule.
+// Calling the returned function removes the Observer
+func (g *Game) AddObserver(o Observer, changes bool) (remove func()) {
+	added := &observer{o, changes}
+	g.observers = append(g.observers, added)
+	return func() {
+		for idx, o := range g.observers {
+			if o == added {
+				g.observers = append(g.observers[:idx:idx], g.observers[idx+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// ClearObservers removes all Observers from the game
+func (g *Game) ClearObservers() {
+	g.observers = nil
+	g.changes = nil
 }
 
 // Validate that a game's contents are consistent
@@ -40,6 +83,11 @@ func (g *Game) Validate() {
 }
 
 func (g *Game) updateAliveState(x int, y int, aliveState bool) {
+	g.updateAliveCounts(g.aliveCount.back, x, y, aliveState)
+}
+
+// updateAliveCounts changes the neighbour counts in counts around a cell
+func (g *Game) updateAliveCounts(counts [][]uint8, x int, y int, aliveState bool) {
 	var absoluteY, absoluteX int
 	for relY := -1; relY <= 1; relY++ {
 		for relX := -1; relX <= 1; relX++ {
@@ -49,9 +97,9 @@ func (g *Game) updateAliveState(x int, y int, aliveState bool) {
 				continue
 			}
 			if aliveState {
-				g.aliveCount.back[absoluteY][absoluteX]++
+				counts[absoluteY][absoluteX]++
 			} else {
-				g.aliveCount.back[absoluteY][absoluteX]--
+				counts[absoluteY][absoluteX]--
 			}
 		}
 	}
@@ -72,27 +120,95 @@ func (g *Game) init() {
 	g.aliveCount.flip()
 }
 
+// Copy returns a deep copy of the game that ticks independently
+// of the original, with its random generator in the same state
+// so both repeat the same Resets. Observers are not copied
+func (g *Game) Copy() Game {
+	c := *g
+	c.Field = g.Field.copy()
+	c.aliveCount = g.aliveCount.copy()
+	c.Rules.Array = append([]Rule(nil), g.Rules.Array...)
+	c.alives = makeAlives(g.alives.x, g.alives.y)
+	for y := range c.alives.array {
+		copy(c.alives.array[y], g.alives.array[y])
+	}
+	if g.rand != nil {
+		c.rand = NewRand(g.rand.State())
+	}
+	c.observers = nil
+	c.changes = nil
+	return c
+}
+
+// Seed the game's random generator used by Reset
+func (g *Game) Seed(seed uint64) {
+	g.rand = NewRand(seed)
+}
+
+// Rand returns the game's random generator
+func (g *Game) Rand() *Rand {
+	if g.rand == nil {
+		g.rand = newGameRand()
+	}
+	return g.rand
+}
+
 // Reset the game to a random initial state
 // But with the same rules
 func (g *Game) Reset() {
 	g.ticks = 0
-	g.Field.Randomize(len(g.Rules.Array))
+	g.resets++
+	g.Field.RandomizeWith(g.Rand(), len(g.Rules.Array))
 	g.alives = makeAlives(g.X, g.Y)
 	g.aliveCount = MakeGridBuffers(g.X, g.Y, true)
 	g.init()
 }
 
+// Ticks returns the amount of times the game has Ticked
+func (g *Game) Ticks() int {
+	return g.ticks
+}
+
+// Population returns the amount of alive cells
+func (g *Game) Population() int {
+	population := 0
+	for y := range g.alives.array {
+		for _, alive := range g.alives.array[y] {
+			if alive {
+				population++
+			}
+		}
+	}
+	return population
+}
+
+// RulePopulation returns the amount of cells using each rule
+func (g *Game) RulePopulation() []int {
+	population := make([]int, len(g.Rules.Array))
+	for y := range g.Field.Front {
+		for _, cell := range g.Field.Front[y] {
+			population[cell]++
+		}
+	}
+	return population
+}
+
 // Tick progresses the game one step forward
 func (g *Game) Tick() {
 	var oldCellRule, newCellRule Rule
 	var nextRuleIdx uint8
 	var cellAlive bool
+	recordChanges := g.recordChanges()
+	g.changes = g.changes[:0]
 	g.aliveCount.CopyFrontToBack()
 	for y := 0; y < g.Y; y++ {
 		for x := 0; x < g.X; x++ {
 			oldCellRule = g.Rules.Array[g.Field.Front[y][x]]
 			nextRuleIdx = oldCellRule.Transitions[g.aliveCount.Front[y][x]]
 			g.Field.back[y][x] = nextRuleIdx
+			if recordChanges && nextRuleIdx != g.Field.Front[y][x] {
+				g.changes = append(g.changes, CellChange{x, y, g.Field.Front[y][x], nextRuleIdx})
+			}
 			newCellRule = g.Rules.Array[nextRuleIdx]
 			cellAlive = newCellRule.Alive
 			if cellAlive != g.alives.array[y][x] {
@@ -103,4 +219,26 @@ func (g *Game) Tick() {
 	g.ticks++
 	g.Field.flip()
 	g.aliveCount.flip()
+	g.notify()
+}
+
+// recordChanges is true when an observer wants the cells that change
+func (g *Game) recordChanges() bool {
+	for _, o := range g.observers {
+		if o.changes {
+			return true
+		}
+	}
+	return false
+}
+
+// notify the observers of the current tick and changes
+func (g *Game) notify() {
+	for _, o := range g.observers {
+		if o.changes {
+			o.function(g, g.ticks, g.changes)
+		} else {
+			o.function(g, g.ticks, nil)
+		}
+	}
 }
@@ -0,0 +1,263 @@
+package gol
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RuleNode is a single Rule within a RuleGraph
+type RuleNode struct {
+	Index  int    `json:"index"`
+	Alive  bool   `json:"alive"`
+	Colour Colour `json:"colour"`
+}
+
+// RuleEdge is a transition from one Rule to another,
+// Counts are the adjacent alive counts (0-8) that cause it
+type RuleEdge struct {
+	From   int   `json:"from"`
+	To     int   `json:"to"`
+	Counts []int `json:"counts"`
+}
+
+// RuleGraph is the state machine encoded by the
+// Transitions of a set of Rules
+type RuleGraph struct {
+	Nodes []RuleNode `json:"nodes"`
+	Edges []RuleEdge `json:"edges"`
+}
+
+// RuleAnalysis summarises the structure of a RuleGraph
+type RuleAnalysis struct {
+	// Absorbing rules always transition to themselves
+	Absorbing []int `json:"absorbing"`
+	// Cycles are groups of rules that can transition
+	// back around to each other
+	Cycles [][]int `json:"cycles"`
+	// Unreachable rules can never appear after the first tick
+	// when starting from the initial rules
+	Unreachable []int `json:"unreachable"`
+}
+
+// MakeRuleGraph builds the transition graph of a set of Rules
+func MakeRuleGraph(rs Rules) RuleGraph {
+	rg := RuleGraph{
+		Nodes: make([]RuleNode, len(rs.Array)),
+		Edges: []RuleEdge{}}
+	for from, ru := range rs.Array {
+		rg.Nodes[from] = RuleNode{from, ru.Alive, ru.Colour}
+		edges := map[int]*RuleEdge{}
+		for count, to := range ru.Transitions {
+			edge, ok := edges[int(to)]
+			if !ok {
+				edge = &RuleEdge{From: from, To: int(to)}
+				edges[int(to)] = edge
+			}
+			edge.Counts = append(edge.Counts, count)
+		}
+		for to := range rs.Array {
+			if edge, ok := edges[to]; ok {
+				rg.Edges = append(rg.Edges, *edge)
+			}
+		}
+	}
+	return rg
+}
+
+// successors of each node, excluding counts that
+// cannot occur because no rule is alive
+func (rg RuleGraph) successors() [][]int {
+	anyAlive := false
+	for _, n := range rg.Nodes {
+		anyAlive = anyAlive || n.Alive
+	}
+	next := make([][]int, len(rg.Nodes))
+	for _, e := range rg.Edges {
+		if !anyAlive && e.Counts[0] != 0 {
+			continue
+		}
+		if e.To < len(rg.Nodes) {
+			next[e.From] = append(next[e.From], e.To)
+		}
+	}
+	return next
+}
+
+// Absorbing returns the rules which only ever transition to themselves
+func (rg RuleGraph) Absorbing() []int {
+	absorbing := []int{}
+	for idx, next := range rg.successors() {
+		if len(next) == 1 && next[0] == idx {
+			absorbing = append(absorbing, idx)
+		}
+	}
+	return absorbing
+}
+
+// Cycles returns every group of two or more rules that can
+// transition back around to each other, as strongly connected components
+func (rg RuleGraph) Cycles() [][]int {
+	next := rg.successors()
+	index := make([]int, len(next))
+	low := make([]int, len(next))
+	onStack := make([]bool, len(next))
+	for idx := range index {
+		index[idx] = -1
+	}
+	var stack []int
+	counter := 0
+	cycles := [][]int{}
+
+	var connect func(v int)
+	connect = func(v int) {
+		index[v] = counter
+		low[v] = counter
+		counter++
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, w := range next[v] {
+			if index[w] == -1 {
+				connect(w)
+				if low[w] < low[v] {
+					low[v] = low[w]
+				}
+			} else if onStack[w] && index[w] < low[v] {
+				low[v] = index[w]
+			}
+		}
+		if low[v] != index[v] {
+			return
+		}
+		var component []int
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			component = append(component, w)
+			if w == v {
+				break
+			}
+		}
+		if len(component) > 1 {
+			sort.Ints(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for v := range next {
+		if index[v] == -1 {
+			connect(v)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	return cycles
+}
+
+// Unreachable returns the rules that cannot be reached from
+// the initial rules, if no initial rules are given every rule
+// is assumed to be present as with a randomized Grid
+func (rg RuleGraph) Unreachable(initial ...int) []int {
+	if len(initial) == 0 {
+		return []int{}
+	}
+	next := rg.successors()
+	seen := make([]bool, len(next))
+	queue := []int{}
+	for _, idx := range initial {
+		if idx >= 0 && idx < len(seen) && !seen[idx] {
+			seen[idx] = true
+			queue = append(queue, idx)
+		}
+	}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, w := range next[v] {
+			if !seen[w] {
+				seen[w] = true
+				queue = append(queue, w)
+			}
+		}
+	}
+	unreachable := []int{}
+	for idx, ok := range seen {
+		if !ok {
+			unreachable = append(unreachable, idx)
+		}
+	}
+	return unreachable
+}
+
+// Analyze a RuleGraph starting from the given initial rules
+func (rg RuleGraph) Analyze(initial ...int) RuleAnalysis {
+	return RuleAnalysis{
+		Absorbing:   rg.Absorbing(),
+		Cycles:      rg.Cycles(),
+		Unreachable: rg.Unreachable(initial...)}
+}
+
+// GridRules returns the rules present in a Grid, for use
+// as the initial rules of an analysis
+func GridRules(grid [][]uint8) []int {
+	var present [256]bool
+	for y := range grid {
+		for _, cell := range grid[y] {
+			present[cell] = true
+		}
+	}
+	rules := []int{}
+	for idx, ok := range present {
+		if ok {
+			rules = append(rules, idx)
+		}
+	}
+	return rules
+}
+
+// JSON exports the graph and its analysis
+func (rg RuleGraph) JSON(initial ...int) ([]byte, error) {
+	return json.MarshalIndent(struct {
+		RuleGraph
+		Analysis RuleAnalysis `json:"analysis"`
+	}{rg, rg.Analyze(initial...)}, "", "  ")
+}
+
+// DOT exports the graph in the Graphviz DOT language,
+// alive rules are drawn with a double circle
+func (rg RuleGraph) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph rules {\n")
+	for _, n := range rg.Nodes {
+		shape := "circle"
+		if n.Alive {
+			shape = "doublecircle"
+		}
+		fmt.Fprintf(&sb, "\t%d [shape=%s, style=filled, fillcolor=\"%s\"];\n",
+			n.Index, shape, n.Colour.Hex())
+	}
+	for _, e := range rg.Edges {
+		fmt.Fprintf(&sb, "\t%d -> %d [label=\"%s\"];\n", e.From, e.To, countRanges(e.Counts))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// countRanges compresses sorted counts, e.g. 0,1,2,5 to 0-2,5
+func countRanges(counts []int) string {
+	var parts []string
+	for i := 0; i < len(counts); {
+		j := i
+		for j+1 < len(counts) && counts[j+1] == counts[j]+1 {
+			j++
+		}
+		if j == i {
+			parts = append(parts, fmt.Sprint(counts[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", counts[i], counts[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
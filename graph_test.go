@@ -0,0 +1,83 @@
+package gol
+
+// Rule graph testing
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGraphConwayEdges(t *testing.T) {
+	rg := MakeRuleGraph(rs)
+
+	if len(rg.Edges) != 4 {
+		t.Fatalf("Expected 4 edges, got %d", len(rg.Edges))
+	}
+
+	birth := rg.Edges[1]
+	if birth.From != 0 || birth.To != 1 || len(birth.Counts) != 1 || birth.Counts[0] != 3 {
+		t.Fatalf("Birth edge not driven by 3 neighbours: %v", birth)
+	}
+
+	survive := rg.Edges[3]
+	if survive.From != 1 || survive.To != 1 || len(survive.Counts) != 2 {
+		t.Fatalf("Survival edge not driven by 2 and 3 neighbours: %v", survive)
+	}
+}
+
+func TestGraphConwayAnalysis(t *testing.T) {
+	a := MakeRuleGraph(rs).Analyze(0)
+
+	if len(a.Absorbing) != 0 {
+		t.Fatalf("Conway rules have no absorbing states, got %v", a.Absorbing)
+	}
+
+	if len(a.Cycles) != 1 || len(a.Cycles[0]) != 2 {
+		t.Fatalf("Conway rules should cycle between dead and alive, got %v", a.Cycles)
+	}
+
+	if len(a.Unreachable) != 0 {
+		t.Fatalf("Alive should be reachable from dead, got %v", a.Unreachable)
+	}
+}
+
+func TestGraphAbsorbingUnreachable(t *testing.T) {
+	var a0 = Rule{true, [9]uint8{1, 1, 1, 1, 1, 1, 1, 1, 1}, Colour{}}
+	var a1 = Rule{false, [9]uint8{1, 1, 1, 1, 1, 1, 1, 1, 1}, Colour{}}
+	var a2 = Rule{false, [9]uint8{0, 0, 0, 0, 0, 0, 0, 0, 0}, Colour{}}
+	a := MakeRuleGraph(Rules{[]Rule{a0, a1, a2}}).Analyze(GridRules([][]uint8{{0, 1}, {1, 0}})...)
+
+	if len(a.Absorbing) != 1 || a.Absorbing[0] != 1 {
+		t.Fatalf("Rule 1 should be absorbing, got %v", a.Absorbing)
+	}
+
+	if len(a.Unreachable) != 1 || a.Unreachable[0] != 2 {
+		t.Fatalf("Rule 2 should be unreachable, got %v", a.Unreachable)
+	}
+
+	if len(a.Cycles) != 0 {
+		t.Fatalf("No cycles expected, got %v", a.Cycles)
+	}
+}
+
+func TestGraphExport(t *testing.T) {
+	rg := MakeRuleGraph(rs)
+
+	dot := rg.DOT()
+	if !strings.Contains(dot, "0 -> 1 [label=\"3\"]") || !strings.Contains(dot, "1 -> 0 [label=\"0-1,4-8\"]") {
+		t.Fatalf("DOT output missing edges:\n%s", dot)
+	}
+
+	data, err := rg.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out RuleGraph
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Edges) != len(rg.Edges) {
+		t.Fatalf("JSON export lost edges")
+	}
+}
@@ -2,9 +2,26 @@ package gol
 
 import (
 	"fmt"
+	"hash/fnv"
 	"sync"
 )
 
+// MaxGridCells is the most cells a grid read from a file can have,
+// so a small file cannot claim a field too big to allocate
+const MaxGridCells = 1 << 26
+
+// checkGridSize returns an error if a grid read from
+// a file would be negative or larger than MaxGridCells
+func checkGridSize(x int, y int) error {
+	if x < 0 || y < 0 {
+		return fmt.Errorf("grid of %d by %d cells cannot be negative", x, y)
+	}
+	if x > 0 && y > MaxGridCells/x {
+		return fmt.Errorf("grid of %d by %d cells is over the limit of %d cells", x, y, MaxGridCells)
+	}
+	return nil
+}
+
 // MakeGrid creates a Grid with given dimensions
 func MakeGrid(x int, y int) [][]uint8 {
 	array := make([][]uint8, y)
@@ -54,6 +71,16 @@ func MakeGridBuffers(x int, y int, lockable bool) GridBuffers {
 	return GridBuffers{x, y, front, back, mutexes}
 }
 
+// copy makes GridBuffers with their own copies of both Grids
+func (grb *GridBuffers) copy() GridBuffers {
+	c := MakeGridBuffers(grb.X, grb.Y, len(grb.mutexes) > 0)
+	for idx := range c.Front {
+		copy(c.Front[idx], grb.Front[idx])
+		copy(c.back[idx], grb.back[idx])
+	}
+	return c
+}
+
 func (grb *GridBuffers) flip() {
 	grb.back, grb.Front = grb.Front, grb.back
 }
@@ -61,13 +88,31 @@ func (grb *GridBuffers) flip() {
 // Randomize a Grid based on the amount of Rules
 // it represents
 func (grb *GridBuffers) Randomize(RuleAmount int) {
+	grb.RandomizeWith(globalRand{}, RuleAmount)
+}
+
+// RandomizeWith randomizes a Grid from a given source
+func (grb *GridBuffers) RandomizeWith(rand Intner, RuleAmount int) {
 	for idxy := range grb.Front {
 		for idxx := range grb.Front[idxy] {
-			grb.Front[idxy][idxx] = uint8(randInt(RuleAmount))
+			grb.Front[idxy][idxx] = uint8(rand.Intn(RuleAmount))
 		}
 	}
 }
 
+// PadGrid returns a copy of a Grid with pad empty cells on every side
+func PadGrid(grid [][]uint8, pad int) [][]uint8 {
+	x := 0
+	if len(grid) > 0 {
+		x = len(grid[0])
+	}
+	padded := MakeGrid(x+pad*2, len(grid)+pad*2)
+	for idx := range grid {
+		copy(padded[idx+pad][pad:], grid[idx])
+	}
+	return padded
+}
+
 // CheckGrid is up to spec
 func CheckGrid(grid [][]uint8, x int, y int) {
 	if len(grid) != y {
@@ -110,6 +155,15 @@ func (grb *GridBuffers) CopyFrontToBack() {
 	}
 }
 
+// HashGrid returns a 64 bit FNV-1a hash of a Grid's contents
+func HashGrid(grid [][]uint8) uint64 {
+	h := fnv.New64a()
+	for idx := range grid {
+		h.Write(grid[idx])
+	}
+	return h.Sum64()
+}
+
 // Print the GridBuffers Arrays
 func (grb *GridBuffers) Print() {
 	fmt.Println("Front Field")
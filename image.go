@@ -0,0 +1,132 @@
+package gol
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+)
+
+// ImageOptions configures how a Game is drawn as an image
+type ImageOptions struct {
+	// CellSize is the width and height of a cell in pixels, defaults to 1
+	CellSize int
+	// Gridlines draws a line between cells when CellSize is at least 2
+	Gridlines bool
+	// GridColour of the lines, defaults to dark grey
+	GridColour color.Color
+}
+
+// Image adapts a Game to image.Image, drawing each cell
+// with the Colour of its Rule. It reads the live field,
+// so it always shows the game's current state
+type Image struct {
+	game      *Game
+	cellSize  int
+	gridlines bool
+	palette   color.Palette
+}
+
+// NRGBA converts a Colour to an opaque 8 bit colour
+func (c Colour) NRGBA() color.NRGBA {
+	return color.NRGBA{channel(c.R), channel(c.G), channel(c.B), 255}
+}
+
+// RGBA lets a Colour be used as a color.Color
+func (c Colour) RGBA() (r, g, b, a uint32) {
+	return c.NRGBA().RGBA()
+}
+
+// NewImage makes an image of a Game
+func NewImage(g *Game, o ImageOptions) *Image {
+	if o.CellSize < 1 {
+		o.CellSize = 1
+	}
+	if o.GridColour == nil {
+		o.GridColour = color.NRGBA{64, 64, 64, 255}
+	}
+	// The grid colour follows the rule colours, as a cell can never index it
+	palette := make(color.Palette, len(g.Rules.Array)+1)
+	for idx, ru := range g.Rules.Array {
+		palette[idx] = ru.Colour.NRGBA()
+	}
+	palette[len(g.Rules.Array)] = o.GridColour
+	return &Image{
+		game:      g,
+		cellSize:  o.CellSize,
+		gridlines: o.Gridlines && o.CellSize >= 2,
+		palette:   palette}
+}
+
+// ColorModel is the palette of rule colours followed by the grid colour
+func (im *Image) ColorModel() color.Model {
+	return im.palette
+}
+
+// Bounds of the image, with an extra pixel for the outer gridlines
+func (im *Image) Bounds() image.Rectangle {
+	width, height := im.game.X*im.cellSize, im.game.Y*im.cellSize
+	if im.gridlines {
+		width++
+		height++
+	}
+	return image.Rect(0, 0, width, height)
+}
+
+// index of the palette colour of a pixel
+func (im *Image) index(x, y int) int {
+	if im.gridlines && (x%im.cellSize == 0 || y%im.cellSize == 0) {
+		return len(im.palette) - 1
+	}
+	return int(im.game.Field.Front[y/im.cellSize][x/im.cellSize])
+}
+
+// At returns the colour of a pixel
+func (im *Image) At(x, y int) color.Color {
+	if !(image.Point{x, y}.In(im.Bounds())) {
+		return color.NRGBA{}
+	}
+	return im.palette[im.index(x, y)]
+}
+
+// Paletted draws the current state into a paletted image,
+// which can only hold 256 colours so a game with 256 rules
+// and gridlines has its gridlines drawn in the first colour
+func (im *Image) Paletted() *image.Paletted {
+	bounds := im.Bounds()
+	palette := im.palette
+	if len(palette) > 256 {
+		palette = palette[:256]
+	}
+	p := image.NewPaletted(bounds, palette)
+	for y := 0; y < bounds.Dy(); y++ {
+		row := p.Pix[y*p.Stride : y*p.Stride+bounds.Dx()]
+		for x := range row {
+			row[x] = uint8(im.index(x, y))
+		}
+	}
+	return p
+}
+
+// EncodePNG writes the current state of a game as a PNG
+func EncodePNG(w io.Writer, g *Game, o ImageOptions) error {
+	im := NewImage(g, o)
+	if len(im.palette) > 256 {
+		return png.Encode(w, im)
+	}
+	return png.Encode(w, im.Paletted())
+}
+
+// SavePNG saves the current state of a game as a PNG file
+func SavePNG(g *Game, Filename string, o ImageOptions) error {
+	f, err := os.Create(Filename)
+	if err != nil {
+		return err
+	}
+	if err := EncodePNG(f, g, o); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,75 @@
+package gol
+
+// Image testing
+
+import (
+	"bytes"
+	"context"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImageCells(t *testing.T) {
+	rules := Rules{[]Rule{r0, r1}}
+	rules.Array[0].Colour = Colour{0, 0, 0}
+	rules.Array[1].Colour = Colour{1, 0.5, 0}
+	g := MakeGame(Options{3, 2, [][]uint8{{0, 1, 0}, {1, 0, 0}}, 2, rules})
+
+	im := NewImage(&g, ImageOptions{CellSize: 4})
+	if im.Bounds().Dx() != 12 || im.Bounds().Dy() != 8 {
+		t.Fatalf("Image not scaled by cell size, got %v", im.Bounds())
+	}
+
+	orange := color.NRGBA{255, 128, 0, 255}
+	if im.At(5, 2) != orange || im.At(1, 5) != orange {
+		t.Fatalf("Alive cells not drawn in their rule colour")
+	}
+	if im.At(0, 0) != (color.NRGBA{0, 0, 0, 255}) {
+		t.Fatalf("Dead cells not drawn in their rule colour")
+	}
+
+	var buf bytes.Buffer
+	if err := EncodePNG(&buf, &g, ImageOptions{CellSize: 4}); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, gr, b, _ := decoded.At(6, 1).RGBA(); r>>8 != 255 || gr>>8 != 128 || b != 0 {
+		t.Fatalf("PNG does not match image")
+	}
+}
+
+func TestImageGridlines(t *testing.T) {
+	g := MakeGame(Options{3, 2, [][]uint8{{1, 1, 1}, {1, 1, 1}}, 2, rs})
+	grey := color.NRGBA{1, 2, 3, 255}
+	im := NewImage(&g, ImageOptions{CellSize: 5, Gridlines: true, GridColour: grey})
+
+	if im.Bounds().Dx() != 16 || im.Bounds().Dy() != 11 {
+		t.Fatalf("Gridlines not included in bounds, got %v", im.Bounds())
+	}
+	if im.At(5, 3) != grey || im.At(3, 10) != grey || im.At(3, 3) == grey {
+		t.Fatalf("Gridlines not drawn between cells")
+	}
+}
+
+func TestSweepThumbnails(t *testing.T) {
+	dir := t.TempDir()
+	c := SweepConfig{
+		Protocol:     SweepProtocol{X: 6, Y: 6, RuleNumber: 2, Seed: 1, Ticks: 3},
+		Entries:      2,
+		Results:      filepath.Join(dir, "results.csv"),
+		ThumbnailDir: filepath.Join(dir, "thumbnails"),
+		Thumbnail:    ImageOptions{CellSize: 2}}
+
+	if err := Sweep(context.Background(), c); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(c.ThumbnailDir, "00000001.png")); err != nil {
+		t.Fatal(err)
+	}
+}
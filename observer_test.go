@@ -0,0 +1,71 @@
+package gol
+
+// Observer testing
+
+import "testing"
+
+func TestObserverChanges(t *testing.T) {
+	y0 := []uint8{0, 0, 0, 0, 0}
+	y1 := []uint8{0, 0, 1, 0, 0}
+	y2 := []uint8{0, 0, 1, 0, 0}
+	y3 := []uint8{0, 0, 1, 0, 0}
+	y4 := []uint8{0, 0, 0, 0, 0}
+	g := MakeGame(Options{5, 5, [][]uint8{y0, y1, y2, y3, y4}, 2, rs})
+
+	var ticks []int
+	var changes []CellChange
+	g.AddObserver(func(g *Game, tick int, c []CellChange) {
+		ticks = append(ticks, tick)
+		changes = append(changes, c...)
+	}, true)
+	plain := 0
+	g.AddObserver(func(g *Game, tick int, c []CellChange) {
+		if c != nil {
+			t.Fatalf("Observer without changes was given changes")
+		}
+		plain++
+	}, false)
+
+	g.Tick()
+	g.Tick()
+
+	if len(ticks) != 2 || ticks[0] != 1 || ticks[1] != 2 || plain != 2 {
+		t.Fatalf("Observers not called after each tick: %v", ticks)
+	}
+
+	// A blinker changes four cells every tick
+	if len(changes) != 8 {
+		t.Fatalf("Expected 8 changes, got %d: %v", len(changes), changes)
+	}
+	for _, c := range changes[:4] {
+		if g.Field.Front[c.Y][c.X] != c.Old {
+			t.Fatalf("Blinker change %v not reverted after second tick", c)
+		}
+	}
+	if changes[0] != (CellChange{2, 1, 1, 0}) {
+		t.Fatalf("Unexpected first change %v", changes[0])
+	}
+
+	g.ClearObservers()
+	g.Tick()
+	if len(ticks) != 2 {
+		t.Fatalf("Observer called after being cleared")
+	}
+}
+
+func TestObserverRemove(t *testing.T) {
+	g := MakeGame(Options{5, 5, [][]uint8{}, 2, rs})
+
+	var first, second int
+	removeFirst := g.AddObserver(func(g *Game, tick int, c []CellChange) { first++ }, false)
+	g.AddObserver(func(g *Game, tick int, c []CellChange) { second++ }, false)
+
+	g.Tick()
+	removeFirst()
+	removeFirst()
+	g.Tick()
+
+	if first != 1 || second != 2 {
+		t.Fatalf("Expected the removed observer to stop, got %d and %d calls", first, second)
+	}
+}
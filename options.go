@@ -11,6 +11,22 @@ type Options struct {
 	Rules      Rules
 }
 
+// Copy returns a deep copy of the Options so that the
+// Grid and Rules are not shared with the original
+func (o Options) Copy() Options {
+	if o.Grid != nil {
+		grid := make([][]uint8, len(o.Grid))
+		for idx := range o.Grid {
+			grid[idx] = append([]uint8(nil), o.Grid[idx]...)
+		}
+		o.Grid = grid
+	}
+	if o.Rules.Array != nil {
+		o.Rules.Array = append([]Rule(nil), o.Rules.Array...)
+	}
+	return o
+}
+
 // MakeGame constructs a game from a given set of options,
 // Which may be missing some options
 func MakeGame(options Options) Game {
@@ -79,13 +95,13 @@ func MakeGame(options Options) Game {
 
 	// Create the game object
 	currentGame := Game{
-		options.X,
-		options.Y,
-		field,
-		options.Rules,
-		alives,
-		aliveCounts,
-		0}
+		X:          options.X,
+		Y:          options.Y,
+		Field:      field,
+		Rules:      options.Rules,
+		alives:     alives,
+		aliveCount: aliveCounts,
+		rand:       newGameRand()}
 
 	// Ensure nothing mismatches
 	currentGame.Validate()
@@ -0,0 +1,97 @@
+package gol
+
+import (
+	"io"
+	"os"
+)
+
+// Pattern is a Grid and its Rules read from or written to
+// one of the pattern formats traded by the Life community
+type Pattern struct {
+	Name     string
+	Comments []string
+	Grid     [][]uint8
+	Rules    Rules
+}
+
+// Options to make a Game of the Pattern, an empty
+// Pattern has a size of 0 by 0
+func (p Pattern) Options() Options {
+	x := 0
+	if len(p.Grid) > 0 {
+		x = len(p.Grid[0])
+	}
+	return Options{
+		X:          x,
+		Y:          len(p.Grid),
+		Grid:       p.Grid,
+		RuleNumber: len(p.Rules.Array),
+		Rules:      p.Rules}
+}
+
+// Pad returns the Pattern with pad empty cells added to every side
+func (p Pattern) Pad(pad int) Pattern {
+	p.Grid = PadGrid(p.Grid, pad)
+	return p
+}
+
+// lifeRules are Conway's Game of Life, used by
+// formats without a rulestring
+func lifeRules() Rules {
+	rs, _ := ParseRuleString("B3/S23")
+	return rs
+}
+
+// patternGrid makes a Grid big enough for the given cells,
+// which may be ragged, and copies them in
+func patternGrid(rows [][]uint8, x, y int) ([][]uint8, error) {
+	if y < len(rows) {
+		y = len(rows)
+	}
+	for _, row := range rows {
+		if x < len(row) {
+			x = len(row)
+		}
+	}
+	if x == 0 {
+		x = 1
+	}
+	if y == 0 {
+		y = 1
+	}
+	if err := checkGridSize(x, y); err != nil {
+		return nil, err
+	}
+	grid := MakeGrid(x, y)
+	for idx, row := range rows {
+		copy(grid[idx], row)
+	}
+	return grid, nil
+}
+
+// loadWith decodes a pattern file into padded Options
+func loadWith(decode func(io.Reader) (Pattern, error), Filename string, pad int) (Options, error) {
+	f, err := os.Open(Filename)
+	if err != nil {
+		return Options{}, err
+	}
+	defer f.Close()
+	p, err := decode(f)
+	if err != nil {
+		return Options{}, err
+	}
+	return p.Pad(pad).Options(), nil
+}
+
+// saveWith encodes a game to a pattern file
+func saveWith(encode func(io.Writer, Pattern) error, G SaveContent, Filename string) error {
+	f, err := os.Create(Filename)
+	if err != nil {
+		return err
+	}
+	if err := encode(f, Pattern{Grid: G.Grid, Rules: Rules{G.Rules}}); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
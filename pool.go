@@ -0,0 +1,139 @@
+package gol
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// ManyConfig configures a pool of games run by RunManyContext
+type ManyConfig struct {
+	// Games is the amount of games to run
+	Games int
+	// Workers is the amount of games run at once,
+	// defaults to the number of CPUs
+	Workers int
+	// Options every game is made from, deep copied per game
+	Options Options
+	// OptionsFunc makes the Options for game i,
+	// used instead of Options when set
+	OptionsFunc func(i int) Options
+	// Run configures when each game stops
+	Run RunConfig
+	// GameFunction drives each game instead of RunContext when set,
+	// the game in the result is the one it was given
+	GameFunction func(g *Game, gameNumber int)
+}
+
+// GameResult is the outcome of a single game in a pool
+type GameResult struct {
+	Index int
+	Game  Game
+	Run   RunResult
+	// Err is set if the game panicked or was never
+	// started because the context was done
+	Err error
+}
+
+// RunManyStream runs games on a bounded pool of workers, sending
+// each result on the returned channel as it finishes. The channel
+// is closed once every game has a result, or once ctx is done when
+// results not yet sent are dropped. Callers that stop reading
+// before the channel is closed must cancel ctx, or the workers
+// block forever waiting to send
+func RunManyStream(ctx context.Context, c ManyConfig) <-chan GameResult {
+	workers := c.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > c.Games {
+		workers = c.Games
+	}
+
+	indexes := make(chan int)
+	results := make(chan GameResult, workers)
+
+	go func() {
+		defer close(indexes)
+		for i := 0; i < c.Games; i++ {
+			select {
+			case indexes <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				select {
+				case results <- runPooled(ctx, c, i):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// RunManyContext runs games on a bounded pool of workers
+// and returns every result ordered by game index, games
+// dropped because ctx was done have a cancelled result
+func RunManyContext(ctx context.Context, c ManyConfig) []GameResult {
+	results := make([]GameResult, c.Games)
+	done := make([]bool, c.Games)
+	for result := range RunManyStream(ctx, c) {
+		results[result.Index] = result
+		done[result.Index] = true
+	}
+	for i := range results {
+		if !done[i] {
+			results[i] = runPooled(ctx, c, i)
+		}
+	}
+	return results
+}
+
+// runPooled makes and runs game i, recovering any panic
+func runPooled(ctx context.Context, c ManyConfig, i int) (result GameResult) {
+	result.Index = i
+	if err := ctx.Err(); err != nil {
+		result.Run = RunResult{Reason: StopCancelled, Err: err}
+		result.Err = err
+		return result
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			result.Run.Reason = StopPanicked
+			result.Err = fmt.Errorf("game %d panicked: %v", i, r)
+		}
+	}()
+
+	var options Options
+	if c.OptionsFunc != nil {
+		options = c.OptionsFunc(i)
+	} else {
+		options = c.Options
+	}
+	result.Game = MakeGame(options.Copy())
+
+	if c.GameFunction != nil {
+		c.GameFunction(&result.Game, i)
+	} else {
+		result.Run = RunContext(ctx, &result.Game, c.Run)
+	}
+	return result
+}
@@ -0,0 +1,127 @@
+package gol
+
+// RunManyContext testing
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestPoolResults(t *testing.T) {
+	results := RunManyContext(context.Background(), ManyConfig{
+		Games:   8,
+		Workers: 3,
+		OptionsFunc: func(i int) Options {
+			return Options{i + 1, 4, [][]uint8{}, 2, rs}
+		},
+		Run: RunConfig{MaxTicks: 3}})
+
+	for i, result := range results {
+		if result.Err != nil {
+			t.Fatal(result.Err)
+		}
+		if result.Index != i || result.Game.X != i+1 {
+			t.Fatalf("Result %d does not match game made for it", i)
+		}
+		if result.Run.Reason != StopMaxTicks || result.Run.Ticks != 3 {
+			t.Fatalf("Game %d did not run for 3 ticks", i)
+		}
+	}
+}
+
+func TestPoolCopiesOptions(t *testing.T) {
+	grid := MakeGrid(5, 5)
+	grid[2][1], grid[2][2], grid[2][3] = 1, 1, 1
+
+	results := RunMany(Options{5, 5, grid, 2, rs}, 4, func(g Game, i int) {
+		g.Tick()
+	})
+
+	if grid[2][1] != 1 || grid[1][2] != 0 {
+		t.Fatalf("Options grid was mutated by running games")
+	}
+	if &results[0].Game.Field.Front[0][0] == &results[1].Game.Field.Front[0][0] {
+		t.Fatalf("Games share a field")
+	}
+}
+
+func TestPoolGameFunction(t *testing.T) {
+	grid := MakeGrid(5, 5)
+	grid[2][1], grid[2][2], grid[2][3] = 1, 1, 1
+	blinker := Options{5, 5, grid, 2, lifeRules()}
+
+	results := RunManyContext(context.Background(), ManyConfig{
+		Games:        2,
+		Options:      blinker,
+		GameFunction: func(g *Game, i int) { g.Tick() }})
+	for _, result := range results {
+		g := result.Game
+		if g.Ticks() != 1 || g.Field.Front[1][2] != 1 || g.Field.Front[2][1] != 0 {
+			t.Fatalf("Expected the game ticked once by the function, got %d ticks", g.Ticks())
+		}
+		// The game is consistent enough to keep ticking
+		g.Tick()
+		if g.Field.Front[2][1] != 1 || g.Field.Front[1][2] != 0 || g.Population() != 3 {
+			t.Fatalf("Returned game does not keep blinking")
+		}
+	}
+
+	// RunMany's TickFunction ticks a copy, leaving the game as made
+	results = RunMany(blinker, 1, func(g Game, i int) { g.Tick() })
+	g := results[0].Game
+	if g.Ticks() != 0 || g.Field.Front[2][1] != 1 || g.Population() != 3 {
+		t.Fatalf("Expected the game as made from RunMany, got %d ticks", g.Ticks())
+	}
+	g.Tick()
+	if g.Field.Front[1][2] != 1 || g.Population() != 3 {
+		t.Fatalf("Game from RunMany does not blink")
+	}
+}
+
+func TestPoolPanic(t *testing.T) {
+	results := RunMany(Options{3, 3, [][]uint8{}, 2, rs}, 2, func(g Game, i int) {
+		if i == 1 {
+			panic("boom")
+		}
+	})
+
+	if results[0].Err != nil || results[1].Err == nil || results[1].Run.Reason != StopPanicked {
+		t.Fatalf("Expected only game 1 to report a panic")
+	}
+}
+
+func TestPoolCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := RunManyContext(ctx, ManyConfig{Games: 4, Options: Options{3, 3, [][]uint8{}, 2, rs}})
+
+	for _, result := range results {
+		if result.Err != context.Canceled || result.Run.Reason != StopCancelled {
+			t.Fatalf("Game %d ran after cancellation", result.Index)
+		}
+	}
+}
+
+func TestPoolStreamStopReading(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	results := RunManyStream(ctx, ManyConfig{
+		Games:   50,
+		Workers: 4,
+		Options: Options{5, 5, [][]uint8{}, 2, rs},
+		Run:     RunConfig{MaxTicks: 3}})
+	<-results
+	cancel()
+
+	// The workers exit without anyone reading their results
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d goroutines once cancelled, got %d", before, runtime.NumGoroutine())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
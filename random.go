@@ -17,3 +17,68 @@ func randInt(i int) int {
 	randMutex.Unlock()
 	return integer
 }
+
+// Intner is a source of random integers in [0, n),
+// *rand.Rand satisfies it for seeded randomization
+type Intner interface {
+	Intn(n int) int
+}
+
+// globalRand is the goroutine safe package generator as an Intner
+type globalRand struct{}
+
+func (globalRand) Intn(n int) int {
+	return randInt(n)
+}
+
+// Rand is a splitmix64 generator whose whole state is a
+// single uint64, so a Game's randomness can be saved and resumed
+type Rand struct {
+	state uint64
+}
+
+// NewRand makes a generator from a seed
+func NewRand(seed uint64) *Rand {
+	return &Rand{seed}
+}
+
+// newGameRand seeds a generator from the package generator
+func newGameRand() *Rand {
+	randMutex.Lock()
+	seed := r.Uint64()
+	randMutex.Unlock()
+	return NewRand(seed)
+}
+
+// Uint64 returns the next random value
+func (ra *Rand) Uint64() uint64 {
+	ra.state += 0x9e3779b97f4a7c15
+	z := ra.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Intn returns a random integer in [0, n) without modulo bias
+func (ra *Rand) Intn(n int) int {
+	if n <= 0 {
+		panic("invalid argument to Intn")
+	}
+	limit := ^uint64(0) - ^uint64(0)%uint64(n)
+	for {
+		v := ra.Uint64()
+		if v < limit {
+			return int(v % uint64(n))
+		}
+	}
+}
+
+// State returns the generator's state for saving
+func (ra *Rand) State() uint64 {
+	return ra.state
+}
+
+// SetState restores a saved state
+func (ra *Rand) SetState(state uint64) {
+	ra.state = state
+}
@@ -0,0 +1,380 @@
+package gol
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+// Recordings are gzip compressed and contain a header, the
+// starting SaveContent, then one frame per tick. A frame is either
+// a delta of the cells that changed or a keyframe of the whole field
+
+const recordingMagic = "GOLREC"
+const recordingVersion = 1
+
+// maxRecordingHeader is room for the JSON of the largest grid and its rules
+const maxRecordingHeader = 16 * MaxGridCells
+
+const (
+	frameDelta byte = 1
+	frameKey   byte = 2
+)
+
+// Recorder writes every Tick of a Game to a recording
+type Recorder struct {
+	gz       *gzip.Writer
+	w        *bufio.Writer
+	interval int
+	start    int
+	tick     int
+	resets   int
+	remove   func()
+	// edits are the cells edited since the last tick, by position
+	edits  map[int]uint8
+	merged []CellChange
+	closed bool
+	err    error
+}
+
+// NewRecorder starts recording a Game from its current tick,
+// writing a keyframe every keyframeInterval ticks. Cells edited
+// between ticks are recorded with the next tick. Recording stops
+// with an error once the game is Reset, or if the tick count does
+// not go up by one every Tick
+func NewRecorder(w io.Writer, g *Game, keyframeInterval int) (*Recorder, error) {
+	if keyframeInterval <= 0 {
+		return nil, errors.New("keyframe interval must be positive")
+	}
+	gz := gzip.NewWriter(w)
+	rec := &Recorder{
+		gz:       gz,
+		w:        bufio.NewWriter(gz),
+		interval: keyframeInterval,
+		start:    g.ticks,
+		tick:     g.ticks,
+		resets:   g.resets,
+		edits:    map[int]uint8{}}
+
+	content, err := json.Marshal(g.SaveContent())
+	if err != nil {
+		return nil, err
+	}
+	rec.w.WriteString(recordingMagic)
+	rec.w.WriteByte(recordingVersion)
+	rec.writeUvarint(uint64(keyframeInterval))
+	rec.writeUvarint(uint64(g.ticks))
+	rec.writeUvarint(uint64(len(content)))
+	rec.w.Write(content)
+
+	rec.remove = g.AddObserver(rec.observe, true)
+	return rec, nil
+}
+
+func (rec *Recorder) writeUvarint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	rec.w.Write(buf[:n])
+}
+
+func (rec *Recorder) observe(g *Game, tick int, changes []CellChange) {
+	if rec.closed || rec.err != nil {
+		return
+	}
+	if g.resets != rec.resets {
+		rec.err = fmt.Errorf("recording stopped at tick %d, the game was reset", rec.tick)
+		return
+	}
+	if tick == rec.tick {
+		for _, c := range changes {
+			rec.edits[c.Y*g.X+c.X] = c.New
+		}
+		return
+	}
+	if tick != rec.tick+1 {
+		rec.err = fmt.Errorf("recording stopped at tick %d, the game went to tick %d", rec.tick, tick)
+		return
+	}
+	rec.tick = tick
+	if (tick-rec.start)%rec.interval == 0 {
+		rec.w.WriteByte(frameKey)
+		for y := range g.Field.Front {
+			rec.w.Write(g.Field.Front[y])
+		}
+	} else {
+		if len(rec.edits) > 0 {
+			changes = rec.merge(g, changes)
+		}
+		rec.w.WriteByte(frameDelta)
+		rec.writeUvarint(uint64(len(changes)))
+		previous := -1
+		for _, c := range changes {
+			position := c.Y*g.X + c.X
+			rec.writeUvarint(uint64(position - previous - 1))
+			rec.w.WriteByte(c.New)
+			previous = position
+		}
+	}
+	for position := range rec.edits {
+		delete(rec.edits, position)
+	}
+	// bufio keeps the first write error, an empty write returns it
+	if _, err := rec.w.Write(nil); err != nil {
+		rec.err = err
+	}
+}
+
+// merge the edits since the last tick with the changes of the tick,
+// which come after them, in the order of their positions
+func (rec *Recorder) merge(g *Game, changes []CellChange) []CellChange {
+	for _, c := range changes {
+		rec.edits[c.Y*g.X+c.X] = c.New
+	}
+	merged := rec.merged[:0]
+	for position, rule := range rec.edits {
+		merged = append(merged, CellChange{X: position % g.X, Y: position / g.X, New: rule})
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Y*g.X+merged[i].X < merged[j].Y*g.X+merged[j].X
+	})
+	rec.merged = merged
+	return merged
+}
+
+// Err returns the first error that occurred while recording
+func (rec *Recorder) Err() error {
+	return rec.err
+}
+
+// Close stops recording, removing the Recorder from the game,
+// and flushes the compressed stream. The underlying writer is
+// not closed
+func (rec *Recorder) Close() error {
+	if rec.closed {
+		return rec.err
+	}
+	rec.closed = true
+	rec.remove()
+	if err := rec.w.Flush(); err != nil && rec.err == nil {
+		rec.err = err
+	}
+	if err := rec.gz.Close(); err != nil && rec.err == nil {
+		rec.err = err
+	}
+	return rec.err
+}
+
+// Player gives random access to every tick of a recording
+type Player struct {
+	X, Y  int
+	Rules Rules
+	// Start is the tick the recording began at
+	Start int
+
+	interval  int
+	keyframes [][]uint8
+	// deltas are the encoded changes of each tick after Start,
+	// nil for ticks that are keyframes
+	deltas [][]byte
+
+	cursor     []uint8
+	cursorTick int
+}
+
+// LoadRecording reads a recording from a file
+func LoadRecording(Filename string) (*Player, error) {
+	f, err := os.Open(Filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewPlayer(f)
+}
+
+// NewPlayer reads a whole recording into memory
+func NewPlayer(r io.Reader) (*Player, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	br := bufio.NewReader(gz)
+
+	magic := make([]byte, len(recordingMagic)+1)
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return nil, err
+	}
+	if string(magic[:len(recordingMagic)]) != recordingMagic {
+		return nil, errors.New("not a recording")
+	}
+	if magic[len(recordingMagic)] != recordingVersion {
+		return nil, fmt.Errorf("unsupported recording version %d", magic[len(recordingMagic)])
+	}
+
+	interval, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+	start, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+	length, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+	// The header is read as it arrives, so a corrupt length
+	// cannot allocate more than the recording holds
+	if length > maxRecordingHeader {
+		return nil, fmt.Errorf("recording header of %d bytes is too large", length)
+	}
+	content, err := ioutil.ReadAll(io.LimitReader(br, int64(length)))
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(content)) != length {
+		return nil, io.ErrUnexpectedEOF
+	}
+	sc, err := decodeContent(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(sc.Grid) == 0 || interval == 0 {
+		return nil, errors.New("recording header is invalid")
+	}
+
+	p := &Player{
+		X:        len(sc.Grid[0]),
+		Y:        len(sc.Grid),
+		Rules:    Rules{sc.Rules},
+		Start:    int(start),
+		interval: int(interval)}
+	p.keyframes = [][]uint8{flatten(sc.Grid)}
+
+	for {
+		kind, err := br.ReadByte()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case frameKey:
+			keyframe := make([]uint8, p.X*p.Y)
+			if _, err := io.ReadFull(br, keyframe); err != nil {
+				return nil, err
+			}
+			p.keyframes = append(p.keyframes, keyframe)
+			p.deltas = append(p.deltas, nil)
+		case frameDelta:
+			delta, err := readDelta(br, p.X*p.Y)
+			if err != nil {
+				return nil, err
+			}
+			p.deltas = append(p.deltas, delta)
+		default:
+			return nil, fmt.Errorf("unknown frame type %d", kind)
+		}
+	}
+	return p, nil
+}
+
+// readDelta reads an encoded delta frame, keeping it encoded
+func readDelta(br *bufio.Reader, size int) ([]byte, error) {
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+	delta := binary.AppendUvarint(nil, count)
+	position := uint64(0)
+	for idx := uint64(0); idx < count; idx++ {
+		gap, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, err
+		}
+		position += gap + 1
+		if position > uint64(size) {
+			return nil, errors.New("delta frame changes a cell outside the field")
+		}
+		rule, err := br.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		delta = binary.AppendUvarint(delta, gap)
+		delta = append(delta, rule)
+	}
+	return delta, nil
+}
+
+func flatten(grid [][]uint8) []uint8 {
+	flat := make([]uint8, 0, len(grid)*len(grid[0]))
+	for y := range grid {
+		flat = append(flat, grid[y]...)
+	}
+	return flat
+}
+
+// Len is the amount of ticks in the recording after Start
+func (p *Player) Len() int {
+	return len(p.deltas)
+}
+
+// seek moves the cursor to a tick
+func (p *Player) seek(tick int) error {
+	offset := tick - p.Start
+	if offset < 0 || offset > len(p.deltas) {
+		return fmt.Errorf("tick %d not in recording of ticks %d-%d", tick, p.Start, p.Start+len(p.deltas))
+	}
+	key := offset / p.interval
+	if p.cursor == nil || p.cursorTick > offset || p.cursorTick < key*p.interval {
+		p.cursor = append(p.cursor[:0], p.keyframes[key]...)
+		p.cursorTick = key * p.interval
+	}
+	for p.cursorTick < offset {
+		p.cursorTick++
+		delta := p.deltas[p.cursorTick-1]
+		if delta == nil {
+			copy(p.cursor, p.keyframes[p.cursorTick/p.interval])
+			continue
+		}
+		count, n := binary.Uvarint(delta)
+		delta = delta[n:]
+		position := -1
+		for idx := uint64(0); idx < count; idx++ {
+			gap, n := binary.Uvarint(delta)
+			position += int(gap) + 1
+			p.cursor[position] = delta[n]
+			delta = delta[n+1:]
+		}
+	}
+	return nil
+}
+
+// Grid returns a copy of the field at a tick
+func (p *Player) Grid(tick int) ([][]uint8, error) {
+	if err := p.seek(tick); err != nil {
+		return nil, err
+	}
+	grid := MakeGrid(p.X, p.Y)
+	for y := range grid {
+		copy(grid[y], p.cursor[y*p.X:(y+1)*p.X])
+	}
+	return grid, nil
+}
+
+// Options returns Options that make a Game of the field at a tick
+func (p *Player) Options(tick int) (Options, error) {
+	grid, err := p.Grid(tick)
+	if err != nil {
+		return Options{}, err
+	}
+	rules := Rules{append([]Rule(nil), p.Rules.Array...)}
+	return Options{X: p.X, Y: p.Y, Grid: grid, Rules: rules}, nil
+}
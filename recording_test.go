@@ -0,0 +1,167 @@
+package gol
+
+// Recording testing
+
+// Checking that every tick of a recorded game can be played back
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"testing"
+)
+
+func TestRecordingPlayback(t *testing.T) {
+	copyOpts = opts
+	copyOpts.X = 20
+	copyOpts.Y = 15
+	copyOpts.RuleNumber = 4
+	g := MakeGame(copyOpts)
+	g.Tick()
+
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf, &g, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots := [][][]uint8{copyGrid(g.Field.Front)}
+	for i := 0; i < 35; i++ {
+		g.Tick()
+		snapshots = append(snapshots, copyGrid(g.Field.Front))
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := NewPlayer(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Start != 1 || p.Len() != 35 || p.X != 20 || p.Y != 15 {
+		t.Fatalf("Recording header mismatch, start %d length %d", p.Start, p.Len())
+	}
+
+	// Play forwards, then seek backwards through keyframes
+	order := []int{0, 1, 2, 9, 10, 11, 35, 3, 22, 21, 20, 19}
+	for _, offset := range order {
+		grid, err := p.Grid(p.Start + offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mismatchCheck(snapshots[offset], grid) {
+			t.Fatalf("Tick %d does not match recording", p.Start+offset)
+		}
+	}
+
+	if _, err := p.Grid(p.Start + 36); err == nil {
+		t.Fatalf("Expected an error reading past the end of the recording")
+	}
+
+	o, err := p.Options(p.Start + 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay := MakeGame(o)
+	replay.Tick()
+	if mismatchCheck(snapshots[6], replay.Field.Front) {
+		t.Fatalf("Game made from a recording does not continue the run")
+	}
+}
+
+func TestRecordingDetaches(t *testing.T) {
+	g := MakeGame(Options{8, 8, [][]uint8{}, 2, rs})
+	rec, err := NewRecorder(&bytes.Buffer{}, &g, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(g.observers) != 0 {
+		t.Fatalf("Closed recorder still observes the game")
+	}
+}
+
+func TestRecordingStopsOnReset(t *testing.T) {
+	g := MakeGame(Options{8, 8, [][]uint8{}, 2, rs})
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf, &g, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Tick()
+	before := copyGrid(g.Field.Front)
+	g.Reset()
+	g.Tick()
+
+	if err := rec.Close(); err == nil {
+		t.Fatalf("Expected an error once the game was reset")
+	}
+	p, err := NewPlayer(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	grid, err := p.Grid(p.Start + p.Len())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Len() != 1 || mismatchCheck(before, grid) {
+		t.Fatalf("Expected the recording to end before the reset, got %d ticks", p.Len())
+	}
+}
+
+func copyGrid(grid [][]uint8) [][]uint8 {
+	return Options{Grid: grid}.Copy().Grid
+}
+
+func TestRecordingEdits(t *testing.T) {
+	g := MakeGame(Options{10, 8, [][]uint8{}, 2, rs})
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf, &g, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Edit before ticks that are deltas and keyframes
+	snapshots := [][][]uint8{copyGrid(g.Field.Front)}
+	for i := 0; i < 9; i++ {
+		g.Fill(i, 0, 3, 3, uint8(i%2))
+		g.Set(9, 7, 1)
+		g.Tick()
+		snapshots = append(snapshots, copyGrid(g.Field.Front))
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := NewPlayer(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for offset := range snapshots {
+		grid, err := p.Grid(p.Start + offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mismatchCheck(snapshots[offset], grid) {
+			t.Fatalf("Tick %d does not match the edited game", p.Start+offset)
+		}
+	}
+}
+
+func TestPlayerHeaderLength(t *testing.T) {
+	for _, length := range []uint64{1 << 62, 1000} {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write([]byte(recordingMagic))
+		gz.Write([]byte{recordingVersion, 1, 0})
+		gz.Write(binary.AppendUvarint(nil, length))
+		gz.Write([]byte("{}"))
+		gz.Close()
+
+		if _, err := NewPlayer(&buf); err == nil {
+			t.Fatalf("Expected an error for a header of %d bytes holding 2", length)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package render
+
+import gol "github.com/tomlockwood/gogol"
+
+// Backend draws frames and reads input for a Renderer
+type Backend interface {
+	// Draw a frame of the part of the game the camera shows
+	Draw(g *gol.Game, c Camera)
+	// Poll returns the keys pressed since it was last called
+	Poll() []Key
+	// Mouse is the current state of the pointer
+	Mouse() Mouse
+	// ShouldClose is true once the display has been closed by the user
+	ShouldClose() bool
+	// Close the display
+	Close()
+}
+
+// hudShower is a Backend that can draw the HUD over the game
+type hudShower interface {
+	// ShowHUD sets the lines drawn with the next frames, nil hides the HUD
+	ShowHUD(lines [][]HUDSpan)
+}
+
+// Mouse is the pointer position in pixels from the top left
+// of a window of Width by Height, the buttons held and how
+// far the wheel has scrolled since the mouse was last read
+type Mouse struct {
+	X, Y          float64
+	Width, Height int
+	Left, Right   bool
+	Scroll        float64
+}
+
+// Key is a key on the keyboard, with the same values as GLFW
+type Key int
+
+// Keys with names, printable keys are their upper case character
+const (
+	KeyUnknown      Key = -1
+	KeySpace        Key = ' '
+	KeyComma        Key = ','
+	KeyMinus        Key = '-'
+	KeyPeriod       Key = '.'
+	KeyEqual        Key = '='
+	KeyLeftBracket  Key = '['
+	KeyRightBracket Key = ']'
+	KeyEscape       Key = 256
+	KeyEnter        Key = 257
+	KeyTab          Key = 258
+	KeyBackspace    Key = 259
+	KeyDelete       Key = 261
+	KeyRight        Key = 262
+	KeyLeft         Key = 263
+	KeyDown         Key = 264
+	KeyUp           Key = 265
+	KeyPageUp       Key = 266
+	KeyPageDown     Key = 267
+	KeyHome         Key = 268
+)
+
+// Letter keys
+const (
+	KeyA Key = 'A' + iota
+	KeyB
+	KeyC
+	KeyD
+	KeyE
+	KeyF
+	KeyG
+	KeyH
+	KeyI
+	KeyJ
+	KeyK
+	KeyL
+	KeyM
+	KeyN
+	KeyO
+	KeyP
+	KeyQ
+	KeyR
+	KeyS
+	KeyT
+	KeyU
+	KeyV
+	KeyW
+	KeyX
+	KeyY
+	KeyZ
+)
+
+// Number keys
+const (
+	Key0 Key = '0' + iota
+	Key1
+	Key2
+	Key3
+	Key4
+	Key5
+	Key6
+	Key7
+	Key8
+	Key9
+)
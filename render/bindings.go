@@ -0,0 +1,189 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Command is something a key can be bound to
+type Command func(r *Renderer)
+
+// Bindings are the commands run when keys are pressed
+type Bindings map[Key]Command
+
+// DefaultCommands are the commands bindings can name in configuration
+func DefaultCommands() map[string]Command {
+	commands := map[string]Command{
+		"quit":     (*Renderer).CloseScreen,
+		"new":      (*Renderer).NewGame,
+		"save":     (*Renderer).Save,
+		"reset":    (*Renderer).Reset,
+		"pause":    (*Renderer).TogglePause,
+		"step":     (*Renderer).Step,
+		"rewind":   func(r *Renderer) { r.Rewind() },
+		"faster":   (*Renderer).Faster,
+		"slower":   (*Renderer).Slower,
+		"fps-up":   func(r *Renderer) { r.SetFPS(r.FPS() * 2) },
+		"fps-down": func(r *Renderer) { r.SetFPS(r.FPS() / 2) },
+		"zoom-in":  func(r *Renderer) { r.Zoom(1.25) },
+		"zoom-out": func(r *Renderer) { r.Zoom(1 / 1.25) },
+		"left":     func(r *Renderer) { r.Pan(-0.1, 0) },
+		"right":    func(r *Renderer) { r.Pan(0.1, 0) },
+		"up":       func(r *Renderer) { r.Pan(0, 0.1) },
+		"down":     func(r *Renderer) { r.Pan(0, -0.1) },
+		"follow":   (*Renderer).ToggleFollow,
+		"camera":   (*Renderer).ResetCamera,
+		"open":     func(r *Renderer) { r.reportErr(r.OpenDir(r.SaveDir())) },
+		"next":     func(r *Renderer) { r.reportErr(r.NextFile()) },
+		"previous": func(r *Renderer) { r.reportErr(r.PreviousFile()) },
+		"gallery":  (*Renderer).ToggleGallery,
+		"promote":  (*Renderer).PromoteHovered,
+		"edit":     (*Renderer).ToggleEdit,
+		"select":   (*Renderer).ToggleSelect,
+		"copy":     (*Renderer).Copy,
+		"paste":    (*Renderer).Paste,
+		"erase":    (*Renderer).Erase,
+		"hud":      (*Renderer).ToggleHUD,
+	}
+	for rule := 0; rule <= 9; rule++ {
+		rule := uint8(rule)
+		commands[fmt.Sprintf("rule-%d", rule)] = func(r *Renderer) { r.SetRule(rule) }
+	}
+	return commands
+}
+
+// defaultBindings names the command of each key bound by default
+var defaultBindings = map[string]string{
+	"Escape":    "quit",
+	"Space":     "new",
+	"K":         "save",
+	"R":         "reset",
+	"P":         "pause",
+	"N":         "step",
+	".":         "step",
+	",":         "rewind",
+	"=":         "faster",
+	"-":         "slower",
+	"]":         "fps-up",
+	"[":         "fps-down",
+	"Z":         "zoom-in",
+	"X":         "zoom-out",
+	"Left":      "left",
+	"Right":     "right",
+	"Up":        "up",
+	"Down":      "down",
+	"F":         "follow",
+	"Home":      "camera",
+	"O":         "open",
+	"PageDown":  "next",
+	"PageUp":    "previous",
+	"G":         "gallery",
+	"Enter":     "promote",
+	"E":         "edit",
+	"S":         "select",
+	"C":         "copy",
+	"V":         "paste",
+	"Delete":    "erase",
+	"Backspace": "erase",
+	"H":         "hud",
+	"0":         "rule-0",
+	"1":         "rule-1",
+	"2":         "rule-2",
+	"3":         "rule-3",
+	"4":         "rule-4",
+	"5":         "rule-5",
+	"6":         "rule-6",
+	"7":         "rule-7",
+	"8":         "rule-8",
+	"9":         "rule-9",
+}
+
+// DefaultBindings are the keys ActionDefault responds to
+func DefaultBindings() Bindings {
+	b, err := ParseBindings(nil)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// ParseBindings applies a configuration of key names to command
+// names over the default bindings, a command of "none" unbinds a key
+func ParseBindings(config map[string]string) (Bindings, error) {
+	commands := DefaultCommands()
+	b := Bindings{}
+	for _, layer := range []map[string]string{defaultBindings, config} {
+		for name, command := range layer {
+			k, err := ParseKey(name)
+			if err != nil {
+				return nil, err
+			}
+			if command == "none" {
+				delete(b, k)
+				continue
+			}
+			c, ok := commands[command]
+			if !ok {
+				return nil, fmt.Errorf("unknown command %q for key %s", command, name)
+			}
+			b[k] = c
+		}
+	}
+	return b, nil
+}
+
+// LoadBindings reads a JSON object of key names to command names
+func LoadBindings(Filename string) (Bindings, error) {
+	data, err := ioutil.ReadFile(Filename)
+	if err != nil {
+		return nil, err
+	}
+	var config map[string]string
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	return ParseBindings(config)
+}
+
+// keyNames are the names of keys without a printable character
+var keyNames = map[Key]string{
+	KeySpace:     "Space",
+	KeyEscape:    "Escape",
+	KeyEnter:     "Enter",
+	KeyTab:       "Tab",
+	KeyBackspace: "Backspace",
+	KeyDelete:    "Delete",
+	KeyRight:     "Right",
+	KeyLeft:      "Left",
+	KeyDown:      "Down",
+	KeyUp:        "Up",
+	KeyPageUp:    "PageUp",
+	KeyPageDown:  "PageDown",
+	KeyHome:      "Home",
+}
+
+// String is the name of a key as used in bindings
+func (k Key) String() string {
+	if name, ok := keyNames[k]; ok {
+		return name
+	}
+	if k > ' ' && k < 127 {
+		return string(rune(k))
+	}
+	return fmt.Sprintf("Key(%d)", int(k))
+}
+
+// ParseKey reads the name of a key, letters are case insensitive
+func ParseKey(name string) (Key, error) {
+	for k, n := range keyNames {
+		if strings.EqualFold(n, name) {
+			return k, nil
+		}
+	}
+	if len(name) == 1 && name[0] > ' ' && name[0] < 127 {
+		return Key(strings.ToUpper(name)[0]), nil
+	}
+	return KeyUnknown, fmt.Errorf("unknown key %q", name)
+}
@@ -0,0 +1,136 @@
+package render
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gol "github.com/tomlockwood/gogol"
+)
+
+// saveExtensions are the files gol.LoadFile can read
+var saveExtensions = map[string]bool{
+	".json": true, ".golb": true, ".rle": true, ".cells": true, ".lif": true, ".life": true,
+}
+
+// isSave is true for a file name gol.LoadFile can read, optionally gzipped
+func isSave(name string) bool {
+	name = strings.TrimSuffix(strings.ToLower(name), ".gz")
+	return saveExtensions[filepath.Ext(name)]
+}
+
+// listSaves returns the saves in a directory, sorted by name
+// so saves named after the time are in the order they were made
+func listSaves(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var saves []string
+	for _, info := range infos {
+		if !info.IsDir() && isSave(info.Name()) {
+			saves = append(saves, filepath.Join(dir, info.Name()))
+		}
+	}
+	return saves, nil
+}
+
+// SaveDir is where saves are written and browsed
+func (r *Renderer) SaveDir() string {
+	return r.saveDir
+}
+
+// SetSaveDir changes where saves are written and browsed
+func (r *Renderer) SetSaveDir(dir string) {
+	r.saveDir = dir
+}
+
+// File is the save last loaded, empty if none has been
+func (r *Renderer) File() string {
+	return r.file
+}
+
+// Open a save, or the first save in a directory, browsing the
+// saves beside it. Use it with a path given on startup
+func (r *Renderer) Open(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return r.OpenDir(path)
+	}
+	r.saveDir = filepath.Dir(path)
+	return r.LoadFile(path)
+}
+
+// OpenDir browses a directory of saves, loading the first
+func (r *Renderer) OpenDir(dir string) error {
+	saves, err := listSaves(dir)
+	if err != nil {
+		return err
+	}
+	r.saveDir = dir
+	if len(saves) == 0 {
+		return fmt.Errorf("no saves in %s", dir)
+	}
+	return r.LoadFile(saves[0])
+}
+
+// LoadFile replaces the game with a save, which new games
+// are then made from. The gallery is closed if shown
+func (r *Renderer) LoadFile(Filename string) error {
+	s, err := gol.LoadFile(Filename)
+	if err != nil {
+		return err
+	}
+	r.file = Filename
+	r.gallery = nil
+	r.options = s.Options().Copy()
+	r.SetGame(s.Game())
+	return nil
+}
+
+// NextFile loads the save after the last one loaded, wrapping around
+func (r *Renderer) NextFile() error {
+	return r.stepFile(1)
+}
+
+// PreviousFile loads the save before the last one loaded, wrapping around
+func (r *Renderer) PreviousFile() error {
+	return r.stepFile(-1)
+}
+
+// stepFile moves through the saves, reading the directory
+// every time so new saves are included
+func (r *Renderer) stepFile(step int) error {
+	saves, err := listSaves(r.saveDir)
+	if err != nil {
+		return err
+	}
+	if len(saves) == 0 {
+		return fmt.Errorf("no saves in %s", r.saveDir)
+	}
+	// Start from the first or last save if the current one is not in the directory
+	current := -1
+	if step < 0 {
+		current = len(saves)
+	}
+	for idx, save := range saves {
+		if save == r.file {
+			current = idx
+		}
+	}
+	// Saves that cannot be loaded are reported and skipped
+	for n := 1; n <= len(saves); n++ {
+		next := ((current+n*step)%len(saves) + len(saves)) % len(saves)
+		err = r.LoadFile(saves[next])
+		if err == nil {
+			return nil
+		}
+		r.reportErr(err)
+	}
+	return fmt.Errorf("no saves in %s could be loaded", r.saveDir)
+}
@@ -0,0 +1,78 @@
+package render
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	gol "github.com/tomlockwood/gogol"
+)
+
+func TestBrowseSaves(t *testing.T) {
+	dir := t.TempDir()
+	for idx, name := range []string{"a.json", "b.rle", "c.cells.gz"} {
+		// Alive corners keep pattern formats from trimming the grid
+		grid := gol.MakeGrid(idx+3, 3)
+		grid[0][0], grid[2][idx+2] = 1, 1
+		rules := gol.Rules{Array: []gol.Rule{{}, {Alive: true}}}
+		g := gol.MakeGame(gol.Options{X: idx + 3, Y: 3, Grid: grid, RuleNumber: 2, Rules: rules})
+		if err := gol.SaveFile(g.SaveContent(), filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ioutil.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a save"), 0644)
+
+	r, h, _ := headlessRenderer(0)
+	if err := r.Open(dir); err != nil {
+		t.Fatal(err)
+	}
+	if r.Game().X != 3 || filepath.Base(r.File()) != "a.json" {
+		t.Fatalf("Expected the first save loaded, got %s", r.File())
+	}
+
+	h.Press(0, KeyPageUp)
+	h.Press(1, KeyPageDown)
+	h.Press(2, KeyPageDown)
+	h.Press(3, KeyEscape)
+	r.Render()
+	if r.Game().X != 4 || len(h.Frames[0][0]) != 5 || len(h.Frames[1][0]) != 3 {
+		t.Fatalf("Expected to wrap back to the last save then step forward twice, at %s", r.File())
+	}
+
+	if err := r.Open(filepath.Join(dir, "c.cells.gz")); err != nil || r.SaveDir() != dir {
+		t.Fatalf("Expected a file to be opened and its directory browsed, got %v", err)
+	}
+	r.NewGame()
+	if r.Game().X != 5 {
+		t.Fatalf("Expected new games to be made from the loaded save")
+	}
+
+	if err := r.OpenDir(t.TempDir()); err == nil {
+		t.Fatalf("Expected error for a directory without saves")
+	}
+}
+
+func TestBrowseSkipsBrokenSaves(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.json", "c.json"} {
+		g := gol.MakeGame(gol.Options{X: 4, Y: 3, RuleNumber: 2})
+		if err := gol.SaveFile(g.SaveContent(), filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ioutil.WriteFile(filepath.Join(dir, "b.json"), []byte("{"), 0644)
+
+	r, _, _ := headlessRenderer(0)
+	var errs []error
+	r.SetErrorHandler(func(err error) { errs = append(errs, err) })
+	if err := r.Open(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.LoadFile(filepath.Join(dir, "b.json")); err == nil || filepath.Base(r.File()) != "a.json" {
+		t.Fatalf("Expected a failed load to keep the last file, got %s", r.File())
+	}
+
+	if err := r.NextFile(); err != nil || filepath.Base(r.File()) != "c.json" || len(errs) != 1 {
+		t.Fatalf("Expected the broken save reported and skipped, at %s with %v", r.File(), errs)
+	}
+}
@@ -0,0 +1,121 @@
+package render
+
+import (
+	"math"
+
+	gol "github.com/tomlockwood/gogol"
+)
+
+// Camera is the part of a game shown in the window, measured in cells
+// from the bottom left. X and Y are the centre of the view and Zoom is
+// how many times larger than fitting the whole game each cell is drawn
+type Camera struct {
+	X, Y float64
+	Zoom float64
+}
+
+// View is a rectangle of cells, which need not be whole
+type View struct {
+	Left, Bottom, Width, Height float64
+}
+
+// MakeCamera shows the whole of a game
+func MakeCamera(gameX, gameY int) Camera {
+	return Camera{float64(gameX) / 2, float64(gameY) / 2, 1}
+}
+
+// View is the rectangle of cells the camera shows
+func (c Camera) View(gameX, gameY int) View {
+	width, height := float64(gameX)/c.Zoom, float64(gameY)/c.Zoom
+	return View{c.X - width/2, c.Y - height/2, width, height}
+}
+
+// Clamp keeps the zoom between showing the whole game and a
+// single cell filling the window, and the view inside the game
+func (c Camera) Clamp(gameX, gameY int) Camera {
+	maxZoom := math.Max(math.Max(float64(gameX), float64(gameY)), 1)
+	if !(c.Zoom >= 1) {
+		c.Zoom = 1
+	}
+	c.Zoom = math.Min(c.Zoom, maxZoom)
+	v := c.View(gameX, gameY)
+	c.X = math.Min(math.Max(c.X, v.Width/2), float64(gameX)-v.Width/2)
+	c.Y = math.Min(math.Max(c.Y, v.Height/2), float64(gameY)-v.Height/2)
+	return c
+}
+
+// Point is the position in cells under the pointer
+func (c Camera) Point(m Mouse, gameX, gameY int) (x, y float64) {
+	v := c.View(gameX, gameY)
+	// The window's y axis points down but the game's points up
+	return v.Left + m.X/float64(m.Width)*v.Width,
+		v.Bottom + (1-m.Y/float64(m.Height))*v.Height
+}
+
+// CellAt is the cell under the pointer, false if it is outside the game
+func (c Camera) CellAt(m Mouse, gameX, gameY int) (x, y int, ok bool) {
+	if m.Width <= 0 || m.Height <= 0 {
+		return 0, 0, false
+	}
+	px, py := c.Point(m, gameX, gameY)
+	x, y = int(math.Floor(px)), int(math.Floor(py))
+	return x, y, x >= 0 && y >= 0 && x < gameX && y < gameY
+}
+
+// ZoomAt multiplies the zoom, keeping the point under the pointer still
+func (c Camera) ZoomAt(factor float64, m Mouse, gameX, gameY int) Camera {
+	px, py := c.Point(m, gameX, gameY)
+	c.Zoom *= factor
+	c = c.Clamp(gameX, gameY)
+	v := c.View(gameX, gameY)
+	c.X = px - m.X/float64(m.Width)*v.Width + v.Width/2
+	c.Y = py - (1-m.Y/float64(m.Height))*v.Height + v.Height/2
+	return c.Clamp(gameX, gameY)
+}
+
+// Drag pans the camera so the game follows the pointer moving by dx, dy pixels
+func (c Camera) Drag(dx, dy float64, m Mouse, gameX, gameY int) Camera {
+	v := c.View(gameX, gameY)
+	c.X -= dx / float64(m.Width) * v.Width
+	c.Y += dy / float64(m.Height) * v.Height
+	return c.Clamp(gameX, gameY)
+}
+
+// Visible is the whole cells at least partly shown, as x0, y0 up to but not including x1, y1
+func (c Camera) Visible(gameX, gameY int) (x0, y0, x1, y1 int) {
+	v := c.View(gameX, gameY)
+	x0 = maxInt(int(math.Floor(v.Left)), 0)
+	y0 = maxInt(int(math.Floor(v.Bottom)), 0)
+	x1 = minInt(int(math.Ceil(v.Left+v.Width)), gameX)
+	y1 = minInt(int(math.Ceil(v.Bottom+v.Height)), gameY)
+	return
+}
+
+// TextureTransform maps the window, from 0 to 1 on each axis, into a
+// texture holding only the visible cells, as an offset and a scale
+func (c Camera) TextureTransform(gameX, gameY int) (offsetX, offsetY, scaleX, scaleY float32) {
+	v := c.View(gameX, gameY)
+	x0, y0, x1, y1 := c.Visible(gameX, gameY)
+	width, height := float64(maxInt(x1-x0, 1)), float64(maxInt(y1-y0, 1))
+	return float32((v.Left - float64(x0)) / width), float32((v.Bottom - float64(y0)) / height),
+		float32(v.Width / width), float32(v.Height / height)
+}
+
+// CentreOfMass is the average position of the alive cells,
+// false if there are none
+func CentreOfMass(g *gol.Game) (x, y float64, ok bool) {
+	var sumX, sumY, count float64
+	for cy, row := range g.Field.Front {
+		for cx, rule := range row {
+			if g.Rules.Array[rule].Alive {
+				sumX += float64(cx) + 0.5
+				sumY += float64(cy) + 0.5
+				count++
+			}
+		}
+	}
+	if count == 0 {
+		return 0, 0, false
+	}
+	return sumX / count, sumY / count, true
+}
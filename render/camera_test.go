@@ -0,0 +1,86 @@
+package render
+
+import (
+	"math"
+	"testing"
+
+	gol "github.com/tomlockwood/gogol"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestCameraView(t *testing.T) {
+	c := MakeCamera(100, 50)
+	v := c.View(100, 50)
+	if v.Left != 0 || v.Bottom != 0 || v.Width != 100 || v.Height != 50 {
+		t.Fatalf("Expected the whole game in view, got %+v", v)
+	}
+
+	c.Zoom = 4
+	c.X, c.Y = 0, 1000
+	c = c.Clamp(100, 50)
+	if c.X != 12.5 || c.Y != 43.75 {
+		t.Fatalf("Expected the view clamped into the top left, got %+v", c)
+	}
+	if x0, y0, x1, y1 := c.Visible(100, 50); x0 != 0 || y0 != 37 || x1 != 25 || y1 != 50 {
+		t.Fatalf("Expected visible cells 0, 37 to 25, 50, got %d, %d to %d, %d", x0, y0, x1, y1)
+	}
+
+	c.Zoom = 1000
+	if c.Clamp(100, 50).Zoom != 100 {
+		t.Fatalf("Expected zoom to stop at a cell filling the window")
+	}
+}
+
+func TestCameraPointer(t *testing.T) {
+	m := Mouse{X: 30, Y: 10, Width: 100, Height: 100}
+	c := MakeCamera(10, 10)
+	if x, y, ok := c.CellAt(m, 10, 10); !ok || x != 3 || y != 9 {
+		t.Fatalf("Expected cell 3, 9 under the pointer, got %d, %d", x, y)
+	}
+
+	// Zooming keeps the point under the pointer still
+	px, py := c.Point(m, 10, 10)
+	zoomed := c.ZoomAt(2.5, m, 10, 10)
+	zx, zy := zoomed.Point(m, 10, 10)
+	if zoomed.Zoom != 2.5 || !near(px, zx) || !near(py, zy) {
+		t.Fatalf("Expected %v, %v under the pointer after zooming, got %v, %v", px, py, zx, zy)
+	}
+
+	// Dragging left by half the window moves the view right by half of it
+	dragged := zoomed.Drag(-50, 0, m, 10, 10)
+	if !near(dragged.X-zoomed.X, 2) {
+		t.Fatalf("Expected the view to move 2 cells, moved %v", dragged.X-zoomed.X)
+	}
+}
+
+func TestTextureTransform(t *testing.T) {
+	c := Camera{X: 5, Y: 5, Zoom: 4}
+	// The view is 2.5 cells from 3.75, so cells 3 to 7 are uploaded
+	offsetX, _, scaleX, _ := c.TextureTransform(10, 10)
+	if !near(float64(offsetX), 0.75/4) || !near(float64(scaleX), 2.5/4) {
+		t.Fatalf("Expected offset %v and scale %v, got %v and %v", 0.75/4, 2.5/4, offsetX, scaleX)
+	}
+}
+
+func TestFollow(t *testing.T) {
+	grid := gol.MakeGrid(20, 20)
+	grid[10][11], grid[10][12], grid[11][11] = 1, 1, 1
+	rules := gol.Rules{Array: []gol.Rule{{}, {Alive: true, Transitions: [9]uint8{0, 0, 1, 1}}}}
+
+	x, y, ok := CentreOfMass(&gol.Game{Field: gol.GridBuffers{Front: grid}, Rules: rules})
+	if !ok || !near(x, 11.5+1.0/3) || !near(y, 10.5+1.0/3) {
+		t.Fatalf("Expected the centre of the alive cells, got %v, %v", x, y)
+	}
+
+	r, h := editRenderer(2)
+	r.InitGame(gol.Options{X: 20, Y: 20, Grid: grid, RuleNumber: 2, Rules: rules})
+	h.Press(0, KeyF, KeyZ, KeyZ, KeyZ)
+	r.Render()
+	c := h.Cameras[1]
+	if !r.Following() || !near(c.X, x) || !near(c.Y, y) {
+		t.Fatalf("Expected the camera to follow the alive cells, got %+v", c)
+	}
+}
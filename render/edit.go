@@ -0,0 +1,243 @@
+package render
+
+import "math"
+
+// Tool is what the left mouse button does while editing,
+// the right mouse button always erases
+type Tool int
+
+// Tools for editing
+const (
+	// ToolPaint sets cells to the selected rule
+	ToolPaint Tool = iota
+	// ToolSelect drags out a rectangle to copy or erase
+	ToolSelect
+)
+
+// Selection is a rectangle of cells between two opposite corners
+type Selection struct {
+	X0, Y0, X1, Y1 int
+}
+
+// Rect is the selection's lowest corner and size
+func (s Selection) Rect() (x, y, width, height int) {
+	x, y = minInt(s.X0, s.X1), minInt(s.Y0, s.Y1)
+	return x, y, maxInt(s.X0, s.X1) - x + 1, maxInt(s.Y0, s.Y1) - y + 1
+}
+
+// editor is the state of mouse editing
+type editor struct {
+	editing   bool
+	tool      Tool
+	rule      uint8
+	clipboard [][]uint8
+
+	// The cell under the pointer and the last cell painted
+	cursorX, cursorY int
+	cursor           bool
+	lastX, lastY     int
+	painting         bool
+	paintRule        uint8
+
+	selection Selection
+	selected  bool
+	selecting bool
+}
+
+// cursorShower is a Backend that can show and hide the cursor
+type cursorShower interface {
+	ShowCursor(show bool)
+}
+
+// Editing is true when the mouse edits the game
+func (r *Renderer) Editing() bool {
+	return r.edit.editing
+}
+
+// ToggleEdit turns mouse editing on or off, showing the cursor while editing
+func (r *Renderer) ToggleEdit() {
+	r.edit.editing = !r.edit.editing
+	if c, ok := r.backend.(cursorShower); ok {
+		c.ShowCursor(r.edit.editing)
+	}
+}
+
+// Tool is what the left mouse button does while editing
+func (r *Renderer) Tool() Tool {
+	return r.edit.tool
+}
+
+// SetTool changes what the left mouse button does while editing
+func (r *Renderer) SetTool(t Tool) {
+	r.edit.tool = t
+}
+
+// ToggleSelect switches between painting and selecting
+func (r *Renderer) ToggleSelect() {
+	if r.edit.tool == ToolSelect {
+		r.edit.tool = ToolPaint
+	} else {
+		r.edit.tool = ToolSelect
+	}
+}
+
+// Rule painted with the left mouse button
+func (r *Renderer) Rule() uint8 {
+	return r.edit.rule
+}
+
+// SetRule changes the rule painted, ignoring rules the game does not have
+func (r *Renderer) SetRule(rule uint8) {
+	if int(rule) < len(r.game.Rules.Array) {
+		r.edit.rule = rule
+	}
+}
+
+// clampRule keeps the rule painted within the rules of the game
+func (r *Renderer) clampRule() {
+	if rules := len(r.game.Rules.Array); int(r.edit.rule) >= rules && rules > 0 {
+		r.edit.rule = uint8(rules - 1)
+	}
+}
+
+// Selection is the selected rectangle, false if nothing is selected
+func (r *Renderer) Selection() (Selection, bool) {
+	return r.edit.selection, r.edit.selected
+}
+
+// Copy the selected cells
+func (r *Renderer) Copy() {
+	if r.edit.selected {
+		r.edit.clipboard = r.game.Region(r.edit.selection.Rect())
+	}
+}
+
+// Paste the copied cells with their lowest corner under the pointer
+func (r *Renderer) Paste() {
+	if r.edit.editing && r.edit.cursor && r.edit.clipboard != nil && r.gallery == nil {
+		r.reportErr(r.game.Paste(r.edit.cursorX, r.edit.cursorY, r.edit.clipboard))
+	}
+}
+
+// Erase the selected cells, setting them to the first rule
+func (r *Renderer) Erase() {
+	if r.edit.selected && r.gallery == nil {
+		x, y, width, height := r.edit.selection.Rect()
+		r.reportErr(r.game.Fill(x, y, width, height, 0))
+	}
+}
+
+// CellAt maps a pointer position to the cell drawn under it,
+// false if the pointer is outside the game
+func (r *Renderer) CellAt(m Mouse) (x, y int, ok bool) {
+	return r.camera.CellAt(m, r.game.X, r.game.Y)
+}
+
+// applyMouse zooms with the wheel, and either pans by dragging
+// or edits the game when editing, between ticks
+func (r *Renderer) applyMouse(m Mouse) {
+	if m.Width <= 0 || m.Height <= 0 {
+		return
+	}
+	x, y := r.size()
+	if m.Scroll != 0 {
+		r.camera = r.camera.ZoomAt(math.Pow(1.1, m.Scroll), m, x, y)
+	}
+	e := &r.edit
+	if !e.editing || r.gallery != nil {
+		e.painting, e.selecting = false, false
+		if m.Left && r.dragging {
+			r.camera = r.camera.Drag(m.X-r.lastMouse.X, m.Y-r.lastMouse.Y, m, x, y)
+		}
+		if r.gallery != nil {
+			r.galleryMouse(m)
+		}
+		r.dragging, r.lastMouse = m.Left, m
+		return
+	}
+	r.dragging, r.lastMouse = false, m
+	x, y, ok := r.CellAt(m)
+	e.cursorX, e.cursorY, e.cursor = x, y, ok
+
+	switch {
+	case !ok:
+		e.painting = false
+	case e.tool == ToolSelect && m.Left:
+		if !e.selecting {
+			e.selection = Selection{x, y, x, y}
+			e.selecting, e.selected = true, true
+		}
+		e.selection.X1, e.selection.Y1 = x, y
+	case m.Left || m.Right:
+		rule := e.rule
+		if m.Right {
+			rule = 0
+		}
+		// A new stroke starts when a button is pressed or changed
+		if !e.painting || rule != e.paintRule {
+			e.lastX, e.lastY = x, y
+		}
+		// Paint every cell between frames so fast strokes have no gaps,
+		// reporting only the first error of the stroke
+		var err error
+		line(e.lastX, e.lastY, x, y, func(lx, ly int) {
+			if setErr := r.game.Set(lx, ly, rule); err == nil {
+				err = setErr
+			}
+		})
+		r.reportErr(err)
+		e.lastX, e.lastY, e.painting, e.paintRule = x, y, true, rule
+	}
+	if !m.Left && !m.Right {
+		e.painting, e.selecting = false, false
+	}
+}
+
+// line calls plot for each cell on a line between two cells
+func line(x0, y0, x1, y1 int, plot func(x, y int)) {
+	dx, dy := absInt(x1-x0), -absInt(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		plot(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func absInt(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
@@ -0,0 +1,101 @@
+package render
+
+import (
+	"testing"
+	"time"
+
+	gol "github.com/tomlockwood/gogol"
+)
+
+func editRenderer(frames int) (*Renderer, *Headless) {
+	h := NewHeadless(frames)
+	r := MakeBackend(h, 10)
+	r.SetTicksPerSecond(0)
+	r.sleep = func(d time.Duration) {}
+	r.InitGame(gol.Options{X: 10, Y: 10, Grid: gol.MakeGrid(10, 10), RuleNumber: 3})
+	r.SetPaused(true)
+	return r, h
+}
+
+func TestCellAt(t *testing.T) {
+	r, _ := editRenderer(0)
+	x, y, ok := r.CellAt(Mouse{X: 15, Y: 95, Width: 100, Height: 100})
+	if !ok || x != 1 || y != 0 {
+		t.Fatalf("Expected cell 1, 0 at the bottom left, got %d, %d", x, y)
+	}
+	if _, _, ok := r.CellAt(Mouse{X: 100, Y: 5, Width: 100, Height: 100}); ok {
+		t.Fatalf("Expected no cell outside the window")
+	}
+}
+
+func TestPaint(t *testing.T) {
+	r, h := editRenderer(4)
+	h.Press(0, KeyE, Key2)
+	h.Move(1, Mouse{X: 5, Y: 95, Width: 100, Height: 100, Left: true})
+	h.Move(2, Mouse{X: 45, Y: 95, Width: 100, Height: 100, Left: true})
+	h.Move(3, Mouse{X: 25, Y: 95, Width: 100, Height: 100, Right: true})
+	r.Render()
+
+	// The stroke fills cells 0 to 4 of the bottom row, then one is erased
+	expected := []uint8{2, 2, 0, 2, 2, 0}
+	for x, rule := range expected {
+		if r.Game().Cell(x, 0) != rule {
+			t.Fatalf("Expected bottom row %v, got %v", expected, r.Game().Field.Front[0][:6])
+		}
+	}
+	if len(h.Frames) != 4 || h.Frames[2][0][4] != 2 {
+		t.Fatalf("Painted cells not drawn")
+	}
+}
+
+func TestSelectCopyPaste(t *testing.T) {
+	r, h := editRenderer(5)
+	r.Game().Set(0, 9, 1)
+	r.Game().Set(1, 8, 2)
+	h.Press(0, KeyE, KeyS)
+	h.Move(1, Mouse{X: 5, Y: 5, Width: 100, Height: 100, Left: true})
+	h.Move(2, Mouse{X: 15, Y: 15, Width: 100, Height: 100, Left: true})
+	h.Move(3, Mouse{X: 55, Y: 55, Width: 100, Height: 100})
+	h.Press(3, KeyC, KeyV)
+	h.Press(4, KeyDelete)
+	r.Render()
+
+	s, ok := r.Selection()
+	if x, y, width, height := s.Rect(); !ok || x != 0 || y != 8 || width != 2 || height != 2 {
+		t.Fatalf("Expected a 2x2 selection at 0, 8, got %v", s)
+	}
+	// The top left of the window is cell 0, 9 and the pointer is over cell 5, 4
+	if r.Game().Cell(5, 4) != 0 || r.Game().Cell(6, 4) != 2 || r.Game().Cell(5, 5) != 1 {
+		t.Fatalf("Copied cells not pasted under the pointer")
+	}
+	if r.Game().Cell(0, 9) != 0 || r.Game().Cell(1, 8) != 0 {
+		t.Fatalf("Selection not erased")
+	}
+}
+
+func TestEditFewerRules(t *testing.T) {
+	r, h := editRenderer(4)
+	var errs []error
+	r.SetErrorHandler(func(err error) { errs = append(errs, err) })
+	r.Game().Set(0, 9, 2)
+	h.Press(0, KeyE, KeyS, Key2)
+	h.Move(1, Mouse{X: 5, Y: 5, Width: 100, Height: 100, Left: true})
+	h.Move(2, Mouse{X: 5, Y: 5, Width: 100, Height: 100})
+	h.Press(2, KeyC)
+	r.Render()
+
+	// A game with fewer rules brings the rule painted into range
+	r.InitGame(gol.Options{X: 10, Y: 10, Grid: gol.MakeGrid(10, 10), RuleNumber: 2})
+	if r.Rule() != 1 {
+		t.Fatalf("Expected the rule painted clamped to 1, got %d", r.Rule())
+	}
+
+	// Pasting a rule the game does not have is reported
+	h.MaxFrames = 6
+	h.Move(4, Mouse{X: 55, Y: 55, Width: 100, Height: 100})
+	h.Press(5, KeyV)
+	r.Render()
+	if len(errs) != 1 || r.Game().Cell(5, 4) != 0 {
+		t.Fatalf("Expected the paste error reported, got %v", errs)
+	}
+}
@@ -0,0 +1,195 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	gol "github.com/tomlockwood/gogol"
+)
+
+// Gallery is a set of games with their own random rules,
+// tiled into one field so they can be compared side by side
+type Gallery struct {
+	Games []gol.Game
+	// Columns and Rows of tiles, the first game is at the top left
+	Columns, Rows int
+	// Border is the colour drawn between tiles
+	Border gol.Colour
+
+	tileX, tileY int
+	view         gol.Game
+}
+
+// MakeGallery makes n games of the size of the options, each with
+// its own random rules. The rules of all the games together must fit
+// in the 255 colours left after the border
+func MakeGallery(o gol.Options, n int) (*Gallery, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("gallery needs at least one game")
+	}
+	columns := int(math.Ceil(math.Sqrt(float64(n))))
+	g := &Gallery{
+		Games:   make([]gol.Game, n),
+		Columns: columns,
+		Rows:    (n + columns - 1) / columns,
+		Border:  gol.Colour{R: 0.25, G: 0.25, B: 0.25}}
+
+	ruleAmount := 0
+	for idx := range g.Games {
+		tile := o.Copy()
+		tile.Grid = nil
+		tile.Rules = gol.Rules{}
+		g.Games[idx] = gol.MakeGame(tile)
+		ruleAmount += len(g.Games[idx].Rules.Array)
+	}
+	if ruleAmount >= paletteSize {
+		return nil, fmt.Errorf("gallery of %d games has %d rules, more than %d colours", n, ruleAmount, paletteSize-1)
+	}
+	g.tileX, g.tileY = g.Games[0].X, g.Games[0].Y
+	return g, nil
+}
+
+// Size of the tiled field, with a cell of border between tiles
+func (g *Gallery) Size() (x, y int) {
+	return g.Columns*(g.tileX+1) - 1, g.Rows*(g.tileY+1) - 1
+}
+
+// Tile is the index of the game shown at a cell of the tiled field,
+// false for borders and empty tiles
+func (g *Gallery) Tile(x, y int) (int, bool) {
+	width, height := g.Size()
+	if x < 0 || y < 0 || x >= width || y >= height ||
+		x%(g.tileX+1) == g.tileX || y%(g.tileY+1) == g.tileY {
+		return 0, false
+	}
+	// The first row of the field is drawn at the bottom
+	idx := (g.Rows-1-y/(g.tileY+1))*g.Columns + x/(g.tileX+1)
+	return idx, idx < len(g.Games)
+}
+
+// Tick every game once, in parallel
+func (g *Gallery) Tick() {
+	var wg sync.WaitGroup
+	wg.Add(len(g.Games))
+	for idx := range g.Games {
+		go func(game *gol.Game) {
+			defer wg.Done()
+			game.Tick()
+		}(&g.Games[idx])
+	}
+	wg.Wait()
+}
+
+// Reset every game to a random state with the same rules
+func (g *Gallery) Reset() {
+	for idx := range g.Games {
+		g.Games[idx].Reset()
+	}
+}
+
+// View tiles the games into one field, offsetting the rules of each
+// game so every tile keeps its colours. The returned game is reused
+// by the next call and can only be drawn, not ticked
+func (g *Gallery) View() *gol.Game {
+	width, height := g.Size()
+	if g.view.X != width || g.view.Y != height {
+		g.view = gol.Game{X: width, Y: height,
+			Field: gol.GridBuffers{X: width, Y: height, Front: gol.MakeGrid(width, height)}}
+	}
+
+	g.view.Rules.Array = append(g.view.Rules.Array[:0], gol.Rule{Colour: g.Border})
+	for y := range g.view.Field.Front {
+		for x := range g.view.Field.Front[y] {
+			g.view.Field.Front[y][x] = 0
+		}
+	}
+	for idx := range g.Games {
+		game := &g.Games[idx]
+		offset := uint8(len(g.view.Rules.Array))
+		g.view.Rules.Array = append(g.view.Rules.Array, game.Rules.Array...)
+		left := idx % g.Columns * (g.tileX + 1)
+		bottom := (g.Rows - 1 - idx/g.Columns) * (g.tileY + 1)
+		for y, row := range game.Field.Front {
+			dst := g.view.Field.Front[bottom+y][left : left+g.tileX]
+			for x, rule := range row {
+				dst[x] = rule + offset
+			}
+		}
+	}
+	return &g.view
+}
+
+// Gallery being shown, nil when showing a single game
+func (r *Renderer) Gallery() *Gallery {
+	return r.gallery
+}
+
+// SetGalleryTiles changes how many games ToggleGallery shows
+func (r *Renderer) SetGalleryTiles(n int) {
+	r.galleryTiles = n
+}
+
+// ShowGallery replaces the view with a gallery of n new games
+func (r *Renderer) ShowGallery(n int) error {
+	g, err := MakeGallery(r.options, n)
+	if err != nil {
+		return err
+	}
+	r.gallery = g
+	r.hovering = false
+	r.camera = MakeCamera(g.Size())
+	return nil
+}
+
+// CloseGallery goes back to showing the single game
+func (r *Renderer) CloseGallery() {
+	r.gallery = nil
+	r.camera = MakeCamera(r.game.X, r.game.Y)
+}
+
+// ToggleGallery shows a gallery, or closes the one shown
+func (r *Renderer) ToggleGallery() {
+	if r.gallery != nil {
+		r.CloseGallery()
+	} else {
+		r.reportErr(r.ShowGallery(r.galleryTiles))
+	}
+}
+
+// Promote closes the gallery, showing one of its games on its own
+func (r *Renderer) Promote(idx int) {
+	if r.gallery == nil || idx < 0 || idx >= len(r.gallery.Games) {
+		return
+	}
+	g := r.gallery.Games[idx]
+	r.gallery = nil
+	r.SetGame(g)
+}
+
+// PromoteHovered promotes the gallery game under the pointer
+func (r *Renderer) PromoteHovered() {
+	if r.hovering {
+		r.Promote(r.hovered)
+	}
+}
+
+// galleryMouse tracks the tile under the pointer, and promotes a
+// tile that is clicked without dragging
+func (r *Renderer) galleryMouse(m Mouse) {
+	width, height := r.gallery.Size()
+	x, y, ok := r.camera.CellAt(m, width, height)
+	if ok {
+		r.hovered, r.hovering = r.gallery.Tile(x, y)
+	} else {
+		r.hovering = false
+	}
+
+	if m.Left && !r.lastMouse.Left {
+		r.pressed = m
+	}
+	if !m.Left && r.lastMouse.Left &&
+		math.Abs(m.X-r.pressed.X) < 4 && math.Abs(m.Y-r.pressed.Y) < 4 {
+		r.PromoteHovered()
+	}
+}
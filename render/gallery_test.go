@@ -0,0 +1,79 @@
+package render
+
+import (
+	"testing"
+	"time"
+
+	gol "github.com/tomlockwood/gogol"
+)
+
+func TestGalleryLayout(t *testing.T) {
+	g, err := MakeGallery(gol.Options{X: 4, Y: 3, RuleNumber: 2}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Columns != 3 || g.Rows != 2 {
+		t.Fatalf("Expected 3 columns and 2 rows, got %d and %d", g.Columns, g.Rows)
+	}
+	if x, y := g.Size(); x != 14 || y != 7 {
+		t.Fatalf("Expected a 14x7 field, got %dx%d", x, y)
+	}
+
+	// The first game is at the top left, which is the last rows of the field
+	cases := []struct {
+		x, y, tile int
+		ok         bool
+	}{{0, 6, 0, true}, {5, 4, 1, true}, {4, 4, 0, false}, {0, 0, 3, true}, {13, 0, 0, false}, {0, 3, 0, false}}
+	for _, c := range cases {
+		if tile, ok := g.Tile(c.x, c.y); ok != c.ok || ok && tile != c.tile {
+			t.Fatalf("Expected cell %d, %d to be tile %d %v, got %d %v", c.x, c.y, c.tile, c.ok, tile, ok)
+		}
+	}
+
+	view := g.View()
+	second := &g.Games[1]
+	offset := uint8(1 + len(g.Games[0].Rules.Array))
+	if view.Field.Front[4][5] != second.Cell(0, 0)+offset {
+		t.Fatalf("Tile cells not offset to the tile's rules")
+	}
+	if view.Rules.Array[offset] != second.Rules.Array[0] || view.Field.Front[3][0] != 0 {
+		t.Fatalf("Tile rules or border not in the view")
+	}
+
+	if _, err := MakeGallery(gol.Options{X: 4, Y: 4, RuleNumber: 6}, 50); err == nil {
+		t.Fatalf("Expected error for more rules than colours")
+	}
+}
+
+func TestGalleryPromote(t *testing.T) {
+	h := NewHeadless(0)
+	r := MakeBackend(h, 10)
+	r.SetTicksPerSecond(0)
+	r.sleep = func(time.Duration) {}
+	r.InitGame(gol.Options{X: 10, Y: 10, RuleNumber: 2})
+	r.SetGalleryTiles(4)
+
+	// Click the top right tile of a 21x21 field in a 210x210 window
+	h.Press(1, KeyG)
+	h.Move(2, Mouse{X: 160, Y: 50, Width: 210, Height: 210, Left: true})
+	h.Move(3, Mouse{X: 161, Y: 50, Width: 210, Height: 210})
+	h.Press(4, KeyEscape)
+	var promoted gol.Game
+	r.SetActionFunction(func(r *Renderer) {
+		if r.Gallery() != nil {
+			promoted = r.Gallery().Games[1]
+		}
+		ActionDefault(r)
+	})
+	r.Render()
+
+	if len(h.Frames[2]) != 21 || len(h.Frames[2][0]) != 21 {
+		t.Fatalf("Expected the gallery drawn as one field")
+	}
+	if r.Gallery() != nil || &r.Game().Rules.Array[0] != &promoted.Rules.Array[0] {
+		t.Fatalf("Expected the clicked tile to be promoted")
+	}
+	if len(h.Frames[3]) != 10 {
+		t.Fatalf("Expected the promoted game drawn on its own")
+	}
+}
@@ -0,0 +1,369 @@
+package render
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+	gol "github.com/tomlockwood/gogol"
+)
+
+const (
+	vertexShaderSource = `
+    #version 410
+    layout(location = 0) in vec2 vp;
+    out vec2 uv;
+    void main() {
+        uv = (vp + 1.0) / 2.0;
+        gl_Position = vec4(vp, 0.0, 1.0);
+    }
+` + "\x00"
+
+	// The field texture holds the rule index of each visible cell,
+	// which is looked up in the palette texture of rule colours.
+	// View moves the window into the field texture by the camera
+	fragmentShaderSource = `
+    #version 410
+    in vec2 uv;
+    uniform sampler2D field;
+    uniform sampler2D palette;
+    uniform vec4 view;
+    out vec4 frag_colour;
+    void main() {
+        vec2 cell = uv * view.zw + view.xy;
+        if (any(lessThan(cell, vec2(0.0))) || any(greaterThan(cell, vec2(1.0)))) {
+            frag_colour = vec4(0.0, 0.0, 0.0, 1.0);
+            return;
+        }
+        int rule = int(texture(field, cell).r * 255.0 + 0.5);
+        frag_colour = texelFetch(palette, ivec2(rule, 0), 0);
+    }
+` + "\x00"
+)
+
+// quad covers the screen as a triangle strip
+var quad = []float32{
+	-1, -1,
+	1, -1,
+	-1, 1,
+	1, 1,
+}
+
+// Window configures the window a Renderer draws in
+type Window struct {
+	// Width and Height of the window, or the resolution when fullscreen
+	Width, Height int
+	// Fullscreen draws on the whole primary monitor
+	Fullscreen bool
+	// Resizable lets the user resize a window that is not fullscreen
+	Resizable bool
+	// Title of the window, defaults to AUTOMATA
+	Title string
+}
+
+// glBackend draws with OpenGL in a GLFW window, uploading the field
+// as a texture of rule indexes so the field is one draw call. The
+// field is fitted below the HUD by a Layout, rebuilt on every frame
+// so it follows the window as it is resized
+type glBackend struct {
+	window  *glfw.Window
+	program uint32
+	vao     uint32
+	field   uint32
+	palette uint32
+	view    int32
+	x, y    int
+	indices []uint8
+	colours []uint8
+	keys    []Key
+	scroll  float64
+
+	fullscreen bool
+	layout     Layout
+	// ratio of framebuffer pixels to window coordinates, above 1 on high DPI displays
+	ratio      float64
+	hud        [][]HUDSpan
+	hudField   uint32
+	hudPalette uint32
+	hudX, hudY int
+	hudIndices []uint8
+	hudColours []uint8
+}
+
+// newGLBackend opens the window, it must be used from the main goroutine
+func newGLBackend(w Window) *glBackend {
+	runtime.LockOSThread()
+
+	b := &glBackend{ratio: 1, fullscreen: w.Fullscreen}
+	b.window = initGlfw(w)
+	b.window.SetKeyCallback(b.onKey)
+	b.window.SetScrollCallback(b.onScroll)
+	b.program = initOpenGL()
+	b.vao = makeVao(quad)
+
+	gl.UseProgram(b.program)
+	gl.Uniform1i(gl.GetUniformLocation(b.program, gl.Str("field\x00")), 0)
+	gl.Uniform1i(gl.GetUniformLocation(b.program, gl.Str("palette\x00")), 1)
+	b.view = gl.GetUniformLocation(b.program, gl.Str("view\x00"))
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+
+	b.field = makeTexture()
+	b.palette = makeTexture()
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGB, paletteSize, 1, 0, gl.RGB, gl.UNSIGNED_BYTE, nil)
+	b.hudField = makeTexture()
+	b.hudPalette = makeTexture()
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGB, paletteSize, 1, 0, gl.RGB, gl.UNSIGNED_BYTE, nil)
+	return b
+}
+
+func (b *glBackend) onKey(w *glfw.Window, key glfw.Key, scancode int,
+	action glfw.Action, mods glfw.ModifierKey) {
+	if action == glfw.Press {
+		b.keys = append(b.keys, Key(key))
+	}
+}
+
+func (b *glBackend) onScroll(w *glfw.Window, xoff, yoff float64) {
+	b.scroll += yoff
+}
+
+// ShowHUD sets the lines of the HUD drawn with the next frames, nil hides it
+func (b *glBackend) ShowHUD(lines [][]HUDSpan) {
+	b.hud = lines
+}
+
+// Draw the cells the camera shows in the space left by the HUD,
+// keeping them square however the window has been resized
+func (b *glBackend) Draw(g *gol.Game, c Camera) {
+	width, height := b.window.GetFramebufferSize()
+	if windowWidth, _ := b.window.GetSize(); windowWidth > 0 {
+		b.ratio = float64(width) / float64(windowWidth)
+	}
+	view := c.View(g.X, g.Y)
+	b.layout = MakeLayout(width, height, view.Width, view.Height, hudHeight(len(b.hud)))
+
+	gl.Viewport(0, 0, int32(width), int32(height))
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	gl.UseProgram(b.program)
+	gl.BindVertexArray(b.vao)
+	b.drawField(g, c, height)
+	b.drawHUD(height)
+	b.window.SwapBuffers()
+}
+
+// drawField uploads the visible cells, reallocating the field
+// texture when the number of visible cells changes
+func (b *glBackend) drawField(g *gol.Game, c Camera, height int) {
+	x0, y0, x1, y1 := c.Visible(g.X, g.Y)
+	cellsX, cellsY := x1-x0, y1-y0
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, b.field)
+	b.indices = indexBuffer(b.indices, g, x0, y0, x1, y1)
+	if cellsX != b.x || cellsY != b.y {
+		b.x, b.y = cellsX, cellsY
+		gl.TexImage2D(gl.TEXTURE_2D, 0, gl.R8, int32(cellsX), int32(cellsY), 0, gl.RED, gl.UNSIGNED_BYTE, gl.Ptr(b.indices))
+	} else {
+		gl.TexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, int32(cellsX), int32(cellsY), gl.RED, gl.UNSIGNED_BYTE, gl.Ptr(b.indices))
+	}
+
+	gl.ActiveTexture(gl.TEXTURE0 + 1)
+	gl.BindTexture(gl.TEXTURE_2D, b.palette)
+	b.colours = paletteBuffer(b.colours, g.Rules)
+	gl.TexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, paletteSize, 1, gl.RGB, gl.UNSIGNED_BYTE, gl.Ptr(b.colours))
+
+	offsetX, offsetY, scaleX, scaleY := c.TextureTransform(g.X, g.Y)
+	gl.Uniform4f(b.view, offsetX, offsetY, scaleX, scaleY)
+	viewport(b.layout.Field, height)
+	gl.DrawArrays(gl.TRIANGLE_STRIP, 0, int32(len(quad)/2))
+}
+
+// drawHUD draws the HUD text with the field shader, one texel per font pixel
+func (b *glBackend) drawHUD(height int) {
+	if len(b.hud) == 0 || b.layout.HUD.Height == 0 {
+		return
+	}
+	texelsX := b.layout.HUD.Width / hudScale
+	var texelsY int
+	var colours gol.Rules
+	b.hudIndices, texelsY, colours = hudBuffer(b.hudIndices, b.hud, texelsX)
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, b.hudField)
+	if texelsX != b.hudX || texelsY != b.hudY {
+		b.hudX, b.hudY = texelsX, texelsY
+		gl.TexImage2D(gl.TEXTURE_2D, 0, gl.R8, int32(texelsX), int32(texelsY), 0, gl.RED, gl.UNSIGNED_BYTE, gl.Ptr(b.hudIndices))
+	} else {
+		gl.TexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, int32(texelsX), int32(texelsY), gl.RED, gl.UNSIGNED_BYTE, gl.Ptr(b.hudIndices))
+	}
+
+	gl.ActiveTexture(gl.TEXTURE0 + 1)
+	gl.BindTexture(gl.TEXTURE_2D, b.hudPalette)
+	b.hudColours = paletteBuffer(b.hudColours, colours)
+	gl.TexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, paletteSize, 1, gl.RGB, gl.UNSIGNED_BYTE, gl.Ptr(b.hudColours))
+
+	gl.Uniform4f(b.view, 0, 0, 1, 1)
+	hud := b.layout.HUD
+	hud.Width = texelsX * hudScale
+	viewport(hud, height)
+	gl.DrawArrays(gl.TRIANGLE_STRIP, 0, int32(len(quad)/2))
+}
+
+// viewport draws into a rectangle of a framebuffer height pixels tall,
+// flipping it as OpenGL counts from the bottom of the framebuffer
+func viewport(r Rect, height int) {
+	gl.Viewport(int32(r.X), int32(height-r.Y-r.Height), int32(r.Width), int32(r.Height))
+}
+
+// Poll GLFW for the keys pressed since the last poll
+func (b *glBackend) Poll() []Key {
+	glfw.PollEvents()
+	keys := b.keys
+	b.keys = nil
+	return keys
+}
+
+// Mouse reads the cursor position and buttons from GLFW,
+// relative to the field as it was last drawn
+func (b *glBackend) Mouse() Mouse {
+	m := Mouse{}
+	m.X, m.Y = b.window.GetCursorPos()
+	m.X, m.Y = m.X*b.ratio, m.Y*b.ratio
+	m.Width, m.Height = b.window.GetFramebufferSize()
+	if b.layout.Field.Width > 0 {
+		m = b.layout.Field.Relative(m)
+	}
+	m.Left = b.window.GetMouseButton(glfw.MouseButtonLeft) == glfw.Press
+	m.Right = b.window.GetMouseButton(glfw.MouseButtonRight) == glfw.Press
+	m.Scroll, b.scroll = b.scroll, 0
+	return m
+}
+
+// ShowCursor shows the cursor while editing, when fullscreen it is hidden otherwise
+func (b *glBackend) ShowCursor(show bool) {
+	if show || !b.fullscreen {
+		b.window.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
+	} else {
+		b.window.SetInputMode(glfw.CursorMode, glfw.CursorHidden)
+	}
+}
+
+// ShouldClose when the window is closed
+func (b *glBackend) ShouldClose() bool {
+	return b.window.ShouldClose()
+}
+
+// Close is a no-op so the window can be reused by the next Render
+func (b *glBackend) Close() {}
+
+// initGlfw initializes glfw and returns a Window to use
+func initGlfw(w Window) *glfw.Window {
+	if err := glfw.Init(); err != nil {
+		panic(err)
+	}
+
+	var monitor *glfw.Monitor
+	if w.Fullscreen {
+		monitor = glfw.GetPrimaryMonitor()
+	}
+	if w.Title == "" {
+		w.Title = "AUTOMATA"
+	}
+
+	if w.Resizable && !w.Fullscreen {
+		glfw.WindowHint(glfw.Resizable, glfw.True)
+	} else {
+		glfw.WindowHint(glfw.Resizable, glfw.False)
+	}
+	glfw.WindowHint(glfw.ContextVersionMajor, 4)
+	glfw.WindowHint(glfw.ContextVersionMinor, 1)
+	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
+	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+
+	window, err := glfw.CreateWindow(w.Width, w.Height, w.Title, monitor, nil)
+	if err != nil {
+		panic(err)
+	}
+	window.MakeContextCurrent()
+	if w.Fullscreen {
+		window.SetInputMode(glfw.CursorMode, glfw.CursorHidden)
+	}
+
+	glfw.SwapInterval(1)
+
+	return window
+}
+
+// initOpenGL initializes OpenGL and returns an intiialized program.
+func initOpenGL() uint32 {
+	if err := gl.Init(); err != nil {
+		panic(err)
+	}
+
+	vertexShader, err := compileShader(vertexShaderSource, gl.VERTEX_SHADER)
+	if err != nil {
+		panic(err)
+	}
+	fragmentShader, err := compileShader(fragmentShaderSource, gl.FRAGMENT_SHADER)
+	if err != nil {
+		panic(err)
+	}
+
+	prog := gl.CreateProgram()
+	gl.AttachShader(prog, vertexShader)
+	gl.AttachShader(prog, fragmentShader)
+	gl.LinkProgram(prog)
+	return prog
+}
+
+// makeTexture makes a texture sampled without filtering or wrapping, leaving it bound
+func makeTexture() uint32 {
+	var texture uint32
+	gl.GenTextures(1, &texture)
+	gl.BindTexture(gl.TEXTURE_2D, texture)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	return texture
+}
+
+// makeVao initializes and returns a vertex array from the points provided.
+func makeVao(points []float32) uint32 {
+	var vbo uint32
+	gl.GenBuffers(1, &vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, 4*len(points), gl.Ptr(points), gl.STATIC_DRAW)
+
+	var vao uint32
+	gl.GenVertexArrays(1, &vao)
+	gl.BindVertexArray(vao)
+	gl.EnableVertexAttribArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, 0, nil)
+
+	return vao
+}
+
+func compileShader(source string, shaderType uint32) (uint32, error) {
+	shader := gl.CreateShader(shaderType)
+
+	csources, free := gl.Strs(source)
+	gl.ShaderSource(shader, 1, csources, nil)
+	free()
+	gl.CompileShader(shader)
+
+	var status int32
+	gl.GetShaderiv(shader, gl.COMPILE_STATUS, &status)
+	if status == gl.FALSE {
+		var logLength int32
+		gl.GetShaderiv(shader, gl.INFO_LOG_LENGTH, &logLength)
+
+		log := strings.Repeat("\x00", int(logLength+1))
+		gl.GetShaderInfoLog(shader, logLength, nil, gl.Str(log))
+
+		return 0, fmt.Errorf("failed to compile %v: %v", source, log)
+	}
+
+	return shader, nil
+}
@@ -0,0 +1,87 @@
+package render
+
+import gol "github.com/tomlockwood/gogol"
+
+// Headless is a Backend without a display, it keeps a copy of every
+// frame drawn and replays key presses scripted against frame numbers
+type Headless struct {
+	// Frames drawn so far
+	Frames [][][]uint8
+	// Cameras each frame was drawn with
+	Cameras []Camera
+	// HUDs drawn over each frame, nil when the HUD was hidden
+	HUDs [][][]HUDSpan
+	// MaxFrames closes the display once reached, 0 never closes it
+	MaxFrames int
+
+	hud    [][]HUDSpan
+	keys   map[int][]Key
+	moves  map[int]Mouse
+	mouse  Mouse
+	closed bool
+}
+
+// NewHeadless makes a headless backend that closes after maxFrames
+func NewHeadless(maxFrames int) *Headless {
+	return &Headless{MaxFrames: maxFrames, keys: map[int][]Key{}, moves: map[int]Mouse{}}
+}
+
+// Press scripts keys to be polled before a frame is drawn
+func (h *Headless) Press(frame int, keys ...Key) {
+	h.keys[frame] = append(h.keys[frame], keys...)
+}
+
+// Move scripts the mouse state from a frame onwards,
+// with any scroll only read on that frame
+func (h *Headless) Move(frame int, m Mouse) {
+	h.moves[frame] = m
+}
+
+// Mouse returns the mouse state scripted for the next frame
+func (h *Headless) Mouse() Mouse {
+	if m, ok := h.moves[len(h.Frames)]; ok {
+		delete(h.moves, len(h.Frames))
+		h.mouse = m
+	}
+	m := h.mouse
+	h.mouse.Scroll = 0
+	return m
+}
+
+// ShowHUD sets the HUD recorded with the next frames
+func (h *Headless) ShowHUD(lines [][]HUDSpan) {
+	h.hud = lines
+}
+
+// Draw copies the whole field of the game, the camera and the HUD
+func (h *Headless) Draw(g *gol.Game, c Camera) {
+	h.Cameras = append(h.Cameras, c)
+	h.HUDs = append(h.HUDs, h.hud)
+	frame := make([][]uint8, len(g.Field.Front))
+	for y := range frame {
+		frame[y] = append([]uint8(nil), g.Field.Front[y]...)
+	}
+	h.Frames = append(h.Frames, frame)
+}
+
+// Poll returns the keys scripted for the next frame
+func (h *Headless) Poll() []Key {
+	keys := h.keys[len(h.Frames)]
+	delete(h.keys, len(h.Frames))
+	return keys
+}
+
+// ShouldClose once MaxFrames have been drawn
+func (h *Headless) ShouldClose() bool {
+	return h.MaxFrames > 0 && len(h.Frames) >= h.MaxFrames
+}
+
+// Close the backend
+func (h *Headless) Close() {
+	h.closed = true
+}
+
+// Closed is true once the backend has been closed
+func (h *Headless) Closed() bool {
+	return h.closed
+}
@@ -0,0 +1,163 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	gol "github.com/tomlockwood/gogol"
+)
+
+// HUDSpan is text in the HUD drawn in one colour
+type HUDSpan struct {
+	Text   string
+	Colour gol.Colour
+}
+
+// maxHUDRules is the most rules the HUD lists the population of
+const maxHUDRules = 16
+
+var hudWhite = gol.Colour{R: 1, G: 1, B: 1}
+
+// hudBackground is behind the HUD text, light enough to see black swatches against
+var hudBackground = gol.Colour{R: 0.15, G: 0.15, B: 0.15}
+
+// HUD describes a game for the overlay. The first line has the tick,
+// the fps and the rule string if the rules have one. The second has
+// the population of each rule beside a swatch of its colour
+func HUD(g *gol.Game, ticks int, fps float64) [][]HUDSpan {
+	status := fmt.Sprintf("TICK %d  FPS %.0f", ticks, fps)
+	if rs, ok := g.Rules.RuleString(); ok {
+		status += "  " + rs
+	}
+	lines := [][]HUDSpan{{{strings.ToUpper(status), hudWhite}}}
+	if len(g.Rules.Array) > maxHUDRules {
+		return lines
+	}
+
+	var population []HUDSpan
+	for idx, count := range g.RulePopulation() {
+		population = append(population,
+			HUDSpan{"#", g.Rules.Array[idx].Colour},
+			HUDSpan{fmt.Sprintf("%d ", count), hudWhite})
+	}
+	return append(lines, population)
+}
+
+// The HUD font is 3 by 5 pixels, with a pixel between characters and lines
+const (
+	glyphWidth  = 3
+	glyphHeight = 5
+	// hudScale is the size in pixels of a HUD font pixel
+	hudScale = 3
+)
+
+// glyphs are the HUD font, each row of pixels from the top as 3 bits
+var glyphs = map[rune][glyphHeight]uint8{
+	'0': {7, 5, 5, 5, 7}, '1': {2, 6, 2, 2, 7}, '2': {7, 1, 7, 4, 7}, '3': {7, 1, 7, 1, 7},
+	'4': {5, 5, 7, 1, 1}, '5': {7, 4, 7, 1, 7}, '6': {7, 4, 7, 5, 7}, '7': {7, 1, 1, 2, 2},
+	'8': {7, 5, 7, 5, 7}, '9': {7, 5, 7, 1, 7},
+	'A': {2, 5, 7, 5, 5}, 'B': {6, 5, 6, 5, 6}, 'C': {7, 4, 4, 4, 7}, 'D': {6, 5, 5, 5, 6},
+	'E': {7, 4, 6, 4, 7}, 'F': {7, 4, 6, 4, 4}, 'G': {7, 4, 5, 5, 7}, 'H': {5, 5, 7, 5, 5},
+	'I': {7, 2, 2, 2, 7}, 'J': {1, 1, 1, 5, 7}, 'K': {5, 5, 6, 5, 5}, 'L': {4, 4, 4, 4, 7},
+	'M': {5, 7, 7, 5, 5}, 'N': {6, 5, 5, 5, 5}, 'O': {2, 5, 5, 5, 2}, 'P': {6, 5, 6, 4, 4},
+	'Q': {2, 5, 5, 6, 3}, 'R': {6, 5, 6, 5, 5}, 'S': {3, 4, 2, 1, 6}, 'T': {7, 2, 2, 2, 2},
+	'U': {5, 5, 5, 5, 7}, 'V': {5, 5, 5, 5, 2}, 'W': {5, 5, 7, 7, 5}, 'X': {5, 5, 2, 5, 5},
+	'Y': {5, 5, 2, 2, 2}, 'Z': {7, 1, 2, 4, 7},
+	'/': {1, 1, 2, 4, 4}, ':': {0, 2, 0, 2, 0}, '.': {0, 0, 0, 0, 2}, '-': {0, 0, 7, 0, 0},
+	'#': {7, 7, 7, 7, 7}, ' ': {0, 0, 0, 0, 0},
+}
+
+// hudHeight is the height in pixels of a HUD of lines
+func hudHeight(lines int) int {
+	if lines == 0 {
+		return 0
+	}
+	return (lines*(glyphHeight+1) + 1) * hudScale
+}
+
+// hudBuffer draws lines of text into a texture of width texels, with one
+// texel for each font pixel and the first row at the bottom. Index 0 is
+// the background, and the other indexes are the colours returned
+func hudBuffer(dst []uint8, lines [][]HUDSpan, width int) ([]uint8, int, gol.Rules) {
+	height := hudHeight(len(lines)) / hudScale
+	if cap(dst) < width*height {
+		dst = make([]uint8, width*height)
+	}
+	dst = dst[:width*height]
+	for idx := range dst {
+		dst[idx] = 0
+	}
+
+	colours := gol.Rules{Array: []gol.Rule{{Colour: hudBackground}}}
+	indexes := map[gol.Colour]uint8{}
+	for line, spans := range lines {
+		x, top := 1, 1+line*(glyphHeight+1)
+		for _, span := range spans {
+			idx, ok := indexes[span.Colour]
+			if !ok && len(colours.Array) < paletteSize {
+				idx = uint8(len(colours.Array))
+				indexes[span.Colour] = idx
+				colours.Array = append(colours.Array, gol.Rule{Colour: span.Colour})
+			}
+			for _, char := range span.Text {
+				glyph, ok := glyphs[char]
+				if !ok {
+					glyph = glyphs['#']
+				}
+				for gy, bits := range glyph {
+					row := height - 1 - (top + gy)
+					for gx := 0; gx < glyphWidth; gx++ {
+						if bits&(1<<uint(glyphWidth-1-gx)) != 0 && x+gx < width {
+							dst[row*width+x+gx] = idx
+						}
+					}
+				}
+				x += glyphWidth + 1
+			}
+		}
+	}
+	return dst, height, colours
+}
+
+// HUDShown is true when the HUD is drawn over the game
+func (r *Renderer) HUDShown() bool {
+	return r.hud
+}
+
+// SetHUD shows or hides the HUD
+func (r *Renderer) SetHUD(show bool) {
+	r.hud = show
+}
+
+// ToggleHUD shows the HUD if it is hidden and hides it if it is shown
+func (r *Renderer) ToggleHUD() {
+	r.hud = !r.hud
+}
+
+// FrameRate is the measured frames per second, which
+// falls below FPS when frames take too long to draw
+func (r *Renderer) FrameRate() float64 {
+	return r.frameRate
+}
+
+// measureFrameRate averages the frame rate over recent frames
+func (r *Renderer) measureFrameRate(frame time.Duration) {
+	if frame <= 0 {
+		return
+	}
+	r.frameRate = 0.9*r.frameRate + 0.1*float64(time.Second)/float64(frame)
+}
+
+// showHUD passes the HUD for a frame to backends that can draw it
+func (r *Renderer) showHUD(g *gol.Game, ticks int) {
+	s, ok := r.backend.(hudShower)
+	if !ok {
+		return
+	}
+	if !r.hud {
+		s.ShowHUD(nil)
+		return
+	}
+	s.ShowHUD(HUD(g, ticks, r.frameRate))
+}
@@ -0,0 +1,94 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	gol "github.com/tomlockwood/gogol"
+)
+
+func hudText(line []HUDSpan) string {
+	var text strings.Builder
+	for _, span := range line {
+		text.WriteString(span.Text)
+	}
+	return text.String()
+}
+
+func TestHUD(t *testing.T) {
+	rules, err := gol.ParseRuleString("B3/S23")
+	if err != nil {
+		t.Fatalf("Parsing rules: %v", err)
+	}
+	g := gol.MakeGame(gol.Options{Rules: rules, Grid: [][]uint8{{0, 1, 1}, {0, 0, 1}}})
+
+	lines := HUD(&g, 7, 59.6)
+	if len(lines) != 2 {
+		t.Fatalf("Expected a status and population line, got %d lines", len(lines))
+	}
+	if text := hudText(lines[0]); text != "TICK 7  FPS 60  B3/S23" {
+		t.Fatalf("Unexpected status %q", text)
+	}
+	if text := hudText(lines[1]); text != "#3 #3 " {
+		t.Fatalf("Unexpected population %q", text)
+	}
+	if lines[1][2].Colour != rules.Array[1].Colour {
+		t.Fatalf("Swatch is not the colour of its rule")
+	}
+}
+
+func TestHUDWithoutRuleString(t *testing.T) {
+	g := gol.MakeGame(gol.Options{X: 3, Y: 3, Rules: gol.Rules{Array: make([]gol.Rule, maxHUDRules+1)}})
+	lines := HUD(&g, 0, 10)
+	if len(lines) != 1 || hudText(lines[0]) != "TICK 0  FPS 10" {
+		t.Fatalf("Expected only the tick and fps, got %v", lines)
+	}
+}
+
+func TestHUDBuffer(t *testing.T) {
+	red := gol.Colour{R: 1}
+	buf, height, colours := hudBuffer(nil, [][]HUDSpan{{{"1", hudWhite}, {"?", red}}}, 8)
+	if height != glyphHeight+2 || len(buf) != 8*height {
+		t.Fatalf("Expected an 8 by %d buffer, got %d texels %d high", glyphHeight+2, len(buf), height)
+	}
+	if len(colours.Array) != 3 || colours.Array[0].Colour != hudBackground ||
+		colours.Array[1].Colour != hudWhite || colours.Array[2].Colour != red {
+		t.Fatalf("Unexpected HUD colours %v", colours.Array)
+	}
+
+	// Rows are stored from the bottom, and the unknown ? is drawn as a block
+	expected := []string{
+		"........",
+		"..1..222",
+		".11..222",
+		"..1..222",
+		"..1..222",
+		".111.222",
+		"........",
+	}
+	for row, want := range expected {
+		var got strings.Builder
+		for _, idx := range buf[(height-1-row)*8 : (height-row)*8] {
+			got.WriteByte(".12"[idx])
+		}
+		if got.String() != want {
+			t.Fatalf("Row %d from the top is %q, expected %q", row, got.String(), want)
+		}
+	}
+}
+
+func TestRenderHUD(t *testing.T) {
+	r, h, _ := headlessRenderer(3)
+	h.Press(1, 'H')
+	r.Render()
+
+	if !r.HUDShown() {
+		t.Fatalf("Expected H to show the HUD")
+	}
+	if h.HUDs[0] != nil {
+		t.Fatalf("Expected no HUD before it is shown")
+	}
+	if text := hudText(h.HUDs[2][0]); !strings.HasPrefix(text, "TICK 3  FPS") {
+		t.Fatalf("Expected the HUD to show the tick, got %q", text)
+	}
+}
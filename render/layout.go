@@ -0,0 +1,46 @@
+package render
+
+import "math"
+
+// Rect is an area of the window in pixels from the top left
+type Rect struct {
+	X, Y, Width, Height int
+}
+
+// Layout is where the field and the HUD are drawn in a window
+type Layout struct {
+	Field, HUD Rect
+}
+
+// MakeLayout puts a HUD of hudHeight pixels across the top of the
+// window and fits a view of the field below it, keeping the cells
+// square and centring the field in the space left
+func MakeLayout(windowWidth, windowHeight int, viewWidth, viewHeight float64, hudHeight int) Layout {
+	hudHeight = minInt(maxInt(hudHeight, 0), windowHeight)
+	l := Layout{HUD: Rect{0, 0, windowWidth, hudHeight}}
+	space := Rect{0, hudHeight, windowWidth, windowHeight - hudHeight}
+	if viewWidth <= 0 || viewHeight <= 0 {
+		l.Field = space
+		return l
+	}
+	scale := math.Min(float64(space.Width)/viewWidth, float64(space.Height)/viewHeight)
+	width := int(math.Round(viewWidth * scale))
+	height := int(math.Round(viewHeight * scale))
+	l.Field = Rect{(space.Width - width) / 2, space.Y + (space.Height-height)/2, width, height}
+	return l
+}
+
+// Contains is true when a point is inside the rectangle
+func (r Rect) Contains(x, y float64) bool {
+	return x >= float64(r.X) && y >= float64(r.Y) &&
+		x < float64(r.X+r.Width) && y < float64(r.Y+r.Height)
+}
+
+// Relative makes the mouse relative to a rectangle of the window,
+// so the field can be treated as if it filled the window
+func (r Rect) Relative(m Mouse) Mouse {
+	m.X -= float64(r.X)
+	m.Y -= float64(r.Y)
+	m.Width, m.Height = r.Width, r.Height
+	return m
+}
@@ -0,0 +1,48 @@
+package render
+
+import "testing"
+
+func TestMakeLayoutFitsField(t *testing.T) {
+	l := MakeLayout(800, 600, 40, 20, 0)
+	if l.Field != (Rect{0, 100, 800, 400}) || l.HUD != (Rect{0, 0, 800, 0}) {
+		t.Fatalf("Expected a wide field centred vertically, got %+v", l)
+	}
+
+	l = MakeLayout(800, 600, 10, 20, 0)
+	if l.Field != (Rect{250, 0, 300, 600}) {
+		t.Fatalf("Expected a tall field centred horizontally, got %+v", l.Field)
+	}
+}
+
+func TestMakeLayoutWithHUD(t *testing.T) {
+	l := MakeLayout(800, 600, 40, 20, 60)
+	if l.HUD != (Rect{0, 0, 800, 60}) {
+		t.Fatalf("Expected the HUD across the top, got %+v", l.HUD)
+	}
+	if l.Field != (Rect{0, 130, 800, 400}) {
+		t.Fatalf("Expected the field centred below the HUD, got %+v", l.Field)
+	}
+
+	l = MakeLayout(800, 50, 40, 20, 60)
+	if l.HUD.Height != 50 || l.Field.Height != 0 {
+		t.Fatalf("Expected the HUD clipped to the window, got %+v", l)
+	}
+}
+
+func TestMakeLayoutEmptyView(t *testing.T) {
+	l := MakeLayout(800, 600, 0, 0, 60)
+	if l.Field != (Rect{0, 60, 800, 540}) {
+		t.Fatalf("Expected the field to fill the space below the HUD, got %+v", l.Field)
+	}
+}
+
+func TestRectRelative(t *testing.T) {
+	r := Rect{250, 0, 300, 600}
+	m := r.Relative(Mouse{X: 300, Y: 30, Width: 800, Height: 600, Left: true})
+	if m != (Mouse{X: 50, Y: 30, Width: 300, Height: 600, Left: true}) {
+		t.Fatalf("Mouse not made relative to the field, got %+v", m)
+	}
+	if !r.Contains(250, 0) || r.Contains(550, 10) || r.Contains(100, 10) {
+		t.Fatalf("Contains does not match the rectangle")
+	}
+}
@@ -1,354 +1,452 @@
 package render
 
 import (
-	"fmt"
-	"runtime"
-	"strings"
+	"log"
+	"path/filepath"
 	"sync"
 	"time"
 
-	"github.com/go-gl/gl/v4.1-core/gl"
-	"github.com/go-gl/glfw/v3.2/glfw"
 	gol "github.com/tomlockwood/gogol"
 )
 
-const (
-	vertexShaderSource = `
-    #version 410
-		in vec3 vp;
-    void main() {
-				gl_Position = vec4(vp, 1.0);
-    }
-` + "\x00"
-
-	fragmentShaderSource = `
-		#version 410
-		uniform vec3 v_color;
-    out vec4 frag_colour;
-    void main() {
-        frag_colour = vec4(v_color, 1.0);
-    }
-` + "\x00"
-)
+// Renderer is the rendering object, it owns the game being
+// shown and the input waiting to be acted on. While rendering
+// the game runs on its own goroutine, and actions are called
+// holding the lock that keeps it from ticking
+type Renderer struct {
+	fps     int
+	backend Backend
+	sleep   func(time.Duration)
+
+	mu   sync.Mutex
+	tps  float64
+	snap snapshot
+	wake chan struct{}
+
+	action      Action
+	bindings    Bindings
+	options     gol.Options
+	game        *gol.Game
+	key         Key
+	reacted     bool
+	closeScreen bool
+
+	paused        bool
+	steps         int
+	ticksPerFrame int
+	history       []gol.Checkpoint
+	historySize   int
+
+	edit      editor
+	camera    Camera
+	following bool
+	dragging  bool
+	lastMouse Mouse
+
+	saveDir string
+	file    string
+	onError func(error)
+
+	gallery      *Gallery
+	galleryTiles int
+	hovered      int
+	hovering     bool
+	pressed      Mouse
+
+	hud       bool
+	frameRate float64
+}
 
-var (
-	square = []float32{
-		-0.5, 0.5, 0,
-		-0.5, -0.5, 0,
-		0.5, -0.5, 0,
+// Action is called on every Tick of a Game, with the
+// renderer to read the game and input from and command
+type Action func(r *Renderer)
 
-		-0.5, 0.5, 0,
-		0.5, 0.5, 0,
-		0.5, -0.5, 0,
-	}
-)
+// Make a valid renderer drawing fullscreen with OpenGL
+func Make(width int, height int, fps int) *Renderer {
+	return MakeWindow(Window{Width: width, Height: height, Fullscreen: true}, fps)
+}
 
-// ActionFunction that's run on every tick
-var ActionFunction Action
-var actionKey glfw.Key
-var reacted, closeScreen bool
-var cells [][]*cell
-var game *gol.Game
+// MakeWindow makes a renderer drawing with OpenGL in a configured window
+func MakeWindow(w Window, fps int) *Renderer {
+	return MakeBackend(newGLBackend(w), fps)
+}
 
-// options passed in to the renderer
-var options *gol.Options
+// MakeBackend makes a renderer drawing with any Backend
+func MakeBackend(b Backend, fps int) *Renderer {
+	return &Renderer{
+		fps:           fps,
+		backend:       b,
+		sleep:         time.Sleep,
+		tps:           float64(fps),
+		frameRate:     float64(fps),
+		wake:          make(chan struct{}, 1),
+		action:        ActionDefault,
+		bindings:      DefaultBindings(),
+		reacted:       true,
+		ticksPerFrame: 1,
+		galleryTiles:  9,
+		saveDir:       ".",
+		onError:       logError}
+}
+
+// logError writes an error to the standard logger, which prints to stderr
+func logError(err error) {
+	log.Println(err)
+}
 
 //// EXTERNALLY ACCESSIBLE options
 
 // Reacted represents if any action has occurred that hasn't been responded to
-func Reacted() bool {
-	return reacted
+func (r *Renderer) Reacted() bool {
+	return r.reacted
 }
 
 // Acted lets you indicate you have reacted to a KeyPress
-func Acted() {
-	reacted = true
+func (r *Renderer) Acted() {
+	r.reacted = true
 }
 
 // ActionKey is the key currently pressed
-func ActionKey() glfw.Key {
-	return actionKey
+func (r *Renderer) ActionKey() Key {
+	return r.key
 }
 
 // SetActionFunction for renderer
-func SetActionFunction(a Action) {
-	ActionFunction = a
+func (r *Renderer) SetActionFunction(a Action) {
+	r.action = a
 }
 
 // CloseScreen closes the screen
-func CloseScreen() {
-	closeScreen = true
+func (r *Renderer) CloseScreen() {
+	r.closeScreen = true
 }
 
-// InitGame allows new game creation from an options object
-func InitGame(o gol.Options) {
-	options = &o
-	g := gol.MakeGame(*options)
-	game = &g
-	cells = makeCells(*game)
-	Acted()
+// SetErrorHandler sets the function called with errors from commands,
+// which have nowhere to return them. By default they are logged
+func (r *Renderer) SetErrorHandler(h func(error)) {
+	r.onError = h
 }
 
-// GetGame returns the current game
-func GetGame() gol.Game {
-	return *game
+// reportErr passes an error to the error handler, nil is ignored
+func (r *Renderer) reportErr(err error) {
+	if err != nil && r.onError != nil {
+		r.onError(err)
+	}
 }
 
-// SetGame sets the current game
-func SetGame(g gol.Game) {
-	game = &g
-	cells = makeCells(*game)
+// Bindings are the keys ActionDefault responds to, they can be changed in place
+func (r *Renderer) Bindings() Bindings {
+	return r.bindings
 }
 
-//// INTERNAL OBJECTS
+// SetBindings replaces the keys ActionDefault responds to
+func (r *Renderer) SetBindings(b Bindings) {
+	r.bindings = b
+}
 
-func onKey(w *glfw.Window, key glfw.Key, scancode int,
-	action glfw.Action, mods glfw.ModifierKey) {
-	if action == glfw.Press {
-		actionKey = key
-		reacted = false
-	}
+// InitGame allows new game creation from an options object
+func (r *Renderer) InitGame(o gol.Options) {
+	r.options = o
+	r.NewGame()
+	r.Acted()
 }
 
-// Renderer is the rendering object
-type Renderer struct {
-	width, height, fps int
-	window             *glfw.Window
-	program            uint32
-}
-
-// Action is called on every Tick of a Game
-type Action func()
-
-// ActionDefault is how the game behaves by default
-func ActionDefault() {
-	if reacted {
-	} else if actionKey == glfw.KeyEscape {
-		closeScreen = true
-		reacted = true
-	} else if actionKey == glfw.KeySpace {
-		g := gol.MakeGame(*options)
-		game = &g
-		cells = makeCells(*game)
-		reacted = true
-	} else if actionKey == glfw.KeyK {
-		gol.Save(
-			gol.SaveContent{Rules: game.Rules.Array, Grid: game.Field.Front},
-			fmt.Sprintf("./%s.json", time.Now().Format(time.RFC3339)))
-		reacted = true
-	} else if actionKey == glfw.KeyR {
-		game.Reset()
-		reacted = true
-	} else {
-		reacted = true
+// NewGame replaces the current game with a new one from the options,
+// or every game in the gallery with new ones
+func (r *Renderer) NewGame() {
+	if r.gallery != nil {
+		r.reportErr(r.ShowGallery(len(r.gallery.Games)))
+		return
 	}
+	g := gol.MakeGame(r.options.Copy())
+	r.SetGame(g)
 }
 
-// Make a valid renderer
-func Make(width int, height int, fps int) Renderer {
-	runtime.LockOSThread()
+// Reset the current game, or every game in the gallery,
+// to a random state with the same rules
+func (r *Renderer) Reset() {
+	if r.gallery != nil {
+		r.gallery.Reset()
+		return
+	}
+	r.game.Reset()
+	r.history = nil
+}
 
-	window := initGlfw(width, height)
-	window.SetKeyCallback(onKey)
+// Save the current game, or the gallery game under the
+// pointer, to a file in the save directory named after the time
+func (r *Renderer) Save() {
+	g := r.game
+	if r.gallery != nil {
+		if !r.hovering {
+			return
+		}
+		g = &r.gallery.Games[r.hovered]
+	}
+	r.reportErr(gol.Save(g.SaveContent(), filepath.Join(r.saveDir, time.Now().Format(time.RFC3339)+".json")))
+}
 
-	program := initOpenGL()
+// Paused is true when the game is not ticking on its own
+func (r *Renderer) Paused() bool {
+	return r.paused
+}
 
-	return Renderer{
-		width,
-		height,
-		fps,
-		window,
-		program}
+// SetPaused pauses or resumes the game
+func (r *Renderer) SetPaused(paused bool) {
+	r.paused = paused
 }
 
-// Render game of life
-func (r *Renderer) Render() {
-	r.RenderAction()
+// TogglePause pauses a running game or resumes a paused one
+func (r *Renderer) TogglePause() {
+	r.paused = !r.paused
 }
 
-// RenderAction renders a game with a passed-in actionFunction
-func (r *Renderer) RenderAction() {
-	g := gol.MakeGame(*options)
-	game = &g
-	cells = makeCells(*game)
+// Step pauses the game and advances it a single tick on the next frame
+func (r *Renderer) Step() {
+	r.paused = true
+	r.steps++
+}
 
-	for !r.window.ShouldClose() {
-		t := time.Now()
-		ActionFunction()
-		if closeScreen {
-			closeScreen = false
-			return
-		}
-		game.Tick()
-		draw(*game, cells, r.window, r.program)
-		deltat := time.Second / time.Duration(r.fps)
-		time.Sleep(deltat - time.Since(t))
-	}
+// FPS is the frames drawn every second
+func (r *Renderer) FPS() int {
+	return r.fps
 }
 
-// initGlfw initializes glfw and returns a Window to use
-func initGlfw(width int, height int) *glfw.Window {
-	if err := glfw.Init(); err != nil {
-		panic(err)
+// SetFPS changes the frames drawn every second, at least 1
+func (r *Renderer) SetFPS(fps int) {
+	if fps < 1 {
+		fps = 1
 	}
+	r.fps = fps
+}
 
-	glfw.WindowHint(glfw.Resizable, glfw.False)
-	glfw.WindowHint(glfw.ContextVersionMajor, 4)
-	glfw.WindowHint(glfw.ContextVersionMinor, 1)
-	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+// TicksPerFrame is how many times the game ticks between frames
+func (r *Renderer) TicksPerFrame() int {
+	return r.ticksPerFrame
+}
 
-	window, err := glfw.CreateWindow(width, height, "AUTOMATA", glfw.GetPrimaryMonitor(), nil)
-	if err != nil {
-		panic(err)
+// SetTicksPerFrame changes how many times the game ticks between frames, at least 1
+func (r *Renderer) SetTicksPerFrame(n int) {
+	if n < 1 {
+		n = 1
 	}
-	window.MakeContextCurrent()
-	window.SetInputMode(glfw.CursorMode, glfw.CursorHidden)
-
-	glfw.SwapInterval(1)
-
-	return window
+	r.ticksPerFrame = n
 }
 
-// initOpenGL initializes OpenGL and returns an intiialized program.
-func initOpenGL() uint32 {
-	if err := gl.Init(); err != nil {
-		panic(err)
+// SetHistory keeps the last n ticks so they can be rewound,
+// each is a full copy of the game so large games need a small n
+func (r *Renderer) SetHistory(n int) {
+	r.historySize = n
+	if len(r.history) > n {
+		r.history = r.history[len(r.history)-n:]
 	}
+}
 
-	vertexShader, err := compileShader(vertexShaderSource, gl.VERTEX_SHADER)
-	if err != nil {
-		panic(err)
+// Rewind pauses the game and returns it to the previous tick,
+// false if there is no history to rewind
+func (r *Renderer) Rewind() bool {
+	r.paused = true
+	if len(r.history) == 0 {
+		return false
 	}
-	fragmentShader, err := compileShader(fragmentShaderSource, gl.FRAGMENT_SHADER)
+	g, err := r.history[len(r.history)-1].Game()
 	if err != nil {
-		panic(err)
+		return false
 	}
-
-	prog := gl.CreateProgram()
-	gl.AttachShader(prog, vertexShader)
-	gl.AttachShader(prog, fragmentShader)
-	gl.LinkProgram(prog)
-	return prog
+	r.history = r.history[:len(r.history)-1]
+	r.game = &g
+	return true
 }
 
-func draw(g gol.Game, cells [][]*cell, window *glfw.Window, program uint32) {
-	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
-	gl.UseProgram(program)
-
-	var wg sync.WaitGroup
-
-	wg.Add(g.X * g.Y)
-
-	for y := range cells {
-		for x, c := range cells[y] {
-			func(c cell) {
-				defer wg.Done()
-				color := g.Rules.Array[g.Field.Front[y][x]].Colour
-				gl.Uniform3f(0, color.R, color.G, color.B)
-				c.draw()
-			}(*c)
+// tick the game, remembering its state first if there is history
+func (r *Renderer) tick() {
+	if r.gallery != nil {
+		r.gallery.Tick()
+		return
+	}
+	if r.historySize > 0 {
+		if len(r.history) == r.historySize {
+			r.history = append(r.history[:0], r.history[1:]...)
 		}
+		r.history = append(r.history, r.game.Checkpoint())
 	}
+	r.game.Tick()
+}
 
-	wg.Wait()
+// Options new games are made from
+func (r *Renderer) Options() gol.Options {
+	return r.options
+}
 
-	glfw.PollEvents()
-	window.SwapBuffers()
+// Game returns the current game, while rendering it is only
+// safe to use from an action
+func (r *Renderer) Game() *gol.Game {
+	return r.game
 }
 
-// makeVao initializes and returns a vertex array from the points provided.
-func makeVao(points []float32) uint32 {
-	var vbo uint32
-	gl.GenBuffers(1, &vbo)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
-	gl.BufferData(gl.ARRAY_BUFFER, 4*len(points), gl.Ptr(points), gl.STATIC_DRAW)
+// SetGame sets the current game, forgetting the history of the last
+// one, moving the camera to show all of it and keeping the rule
+// painted within its rules
+func (r *Renderer) SetGame(g gol.Game) {
+	r.game = &g
+	r.history = nil
+	r.camera = MakeCamera(g.X, g.Y)
+	r.clampRule()
+}
 
-	var vao uint32
-	gl.GenVertexArrays(1, &vao)
-	gl.BindVertexArray(vao)
-	gl.EnableVertexAttribArray(0)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 0, nil)
+// Camera is the part of the game shown
+func (r *Renderer) Camera() Camera {
+	return r.camera
+}
 
-	return vao
+// SetCamera changes the part of the game shown
+func (r *Renderer) SetCamera(c Camera) {
+	r.camera = c.Clamp(r.size())
 }
 
-func compileShader(source string, shaderType uint32) (uint32, error) {
-	shader := gl.CreateShader(shaderType)
+// ResetCamera shows the whole game
+func (r *Renderer) ResetCamera() {
+	r.camera = MakeCamera(r.size())
+}
 
-	csources, free := gl.Strs(source)
-	gl.ShaderSource(shader, 1, csources, nil)
-	free()
-	gl.CompileShader(shader)
+// Zoom the camera about the centre of the view
+func (r *Renderer) Zoom(factor float64) {
+	r.camera.Zoom *= factor
+	r.camera = r.camera.Clamp(r.size())
+}
 
-	var status int32
-	gl.GetShaderiv(shader, gl.COMPILE_STATUS, &status)
-	if status == gl.FALSE {
-		var logLength int32
-		gl.GetShaderiv(shader, gl.INFO_LOG_LENGTH, &logLength)
+// Pan the camera by a fraction of the view
+func (r *Renderer) Pan(dx, dy float64) {
+	v := r.camera.View(r.size())
+	r.camera.X += dx * v.Width
+	r.camera.Y += dy * v.Height
+	r.camera = r.camera.Clamp(r.size())
+}
 
-		log := strings.Repeat("\x00", int(logLength+1))
-		gl.GetShaderInfoLog(shader, logLength, nil, gl.Str(log))
+// size of the field shown, the game or the tiled gallery
+func (r *Renderer) size() (x, y int) {
+	if r.gallery != nil {
+		return r.gallery.Size()
+	}
+	return r.game.X, r.game.Y
+}
 
-		return 0, fmt.Errorf("failed to compile %v: %v", source, log)
+// publish copies what is shown for drawing, if force is false
+// only when the draw loop has taken the last copy
+func (r *Renderer) publish(force bool) {
+	if r.gallery == nil {
+		r.snap.write(r.game, force)
+	} else if force || r.snap.want() {
+		r.snap.write(r.gallery.View(), true)
 	}
+}
 
-	return shader, nil
+// Following is true when the camera follows the centre of mass of the alive cells
+func (r *Renderer) Following() bool {
+	return r.following
 }
 
-type cell struct {
-	drawable uint32
+// ToggleFollow starts or stops the camera following the alive cells
+func (r *Renderer) ToggleFollow() {
+	r.following = !r.following
+}
 
-	x int
-	y int
+// ActionDefault runs the command bound to the key pressed
+func ActionDefault(r *Renderer) {
+	if r.Reacted() {
+		return
+	}
+	if c, ok := r.bindings[r.ActionKey()]; ok {
+		c(r)
+	}
+	r.Acted()
 }
 
-func (c *cell) draw() {
-	gl.BindVertexArray(c.drawable)
-	gl.DrawArrays(gl.TRIANGLES, 0, int32(len(square)/3))
+// Render game of life
+func (r *Renderer) Render() {
+	r.RenderAction()
 }
 
-func makeCells(g gol.Game) [][]*cell {
-	cells := make([][]*cell, g.Y)
-	for y := range cells {
-		cells[y] = make([]*cell, g.X)
-		for x := range cells[y] {
-			cells[y][x] = newCell(g, x, y)
-		}
+// RenderAction renders the game with the renderer's action function,
+// making a game from the options if one has not been set
+func (r *Renderer) RenderAction() {
+	if r.game == nil {
+		r.NewGame()
 	}
-	return cells
-}
-
-func newCell(g gol.Game, x, y int) *cell {
-	points := make([]float32, len(square), len(square))
-	copy(points, square)
-
-	for i := 0; i < len(points); i++ {
-		var position float32
-		var size float32
-		switch i % 3 {
-		case 0:
-			size = 1.0 / float32(g.X)
-			position = float32(x) * size
-		case 1:
-			size = 1.0 / float32(g.Y)
-			position = float32(y) * size
-		default:
-			continue
-		}
+	// The first frame is drawn before the simulation has copied anything
+	r.publish(true)
+	defer r.backend.Close()
 
-		if points[i] < 0 {
-			points[i] = (position * 2) - 1
-		} else {
-			points[i] = ((position + size) * 2) - 1
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		r.simulate(stop)
+	}()
+	defer wg.Wait()
+	defer close(stop)
+
+	last := time.Now()
+	for !r.backend.ShouldClose() {
+		t := time.Now()
+		r.measureFrameRate(t.Sub(last))
+		last = t
+		keys := r.backend.Poll()
+		m := r.backend.Mouse()
+
+		r.mu.Lock()
+		r.applyMouse(m)
+		r.act(keys)
+		if r.closeScreen {
+			r.closeScreen = false
+			r.mu.Unlock()
+			return
+		}
+		if r.tps == 0 {
+			if !r.paused {
+				for n := 0; n < r.ticksPerFrame; n++ {
+					r.tick()
+				}
+			}
+			for ; r.steps > 0; r.steps-- {
+				r.tick()
+			}
+		}
+		// Keys and edits may have changed the game, which the simulation
+		// will not copy for drawing if it is tied to frames or paused
+		changed := len(keys) > 0 || r.edit.editing && (m.Left || m.Right)
+		r.publish(changed || r.tps == 0 || r.paused)
+		r.mu.Unlock()
+		r.wakeSimulation()
+
+		g, ticks := r.snap.read()
+		if r.following {
+			if x, y, ok := CentreOfMass(g); ok {
+				r.camera.X, r.camera.Y = x, y
+			}
+		}
+		r.camera = r.camera.Clamp(g.X, g.Y)
+		r.showHUD(g, ticks)
+		r.backend.Draw(g, r.camera)
+		if wait := time.Second/time.Duration(r.fps) - time.Since(t); wait > 0 {
+			r.sleep(wait)
 		}
 	}
+}
 
-	return &cell{
-		drawable: makeVao(points),
-
-		x: x,
-		y: y,
+// act runs the action function for each key pressed,
+// or once with the last key if none were pressed
+func (r *Renderer) act(keys []Key) {
+	for _, k := range keys {
+		r.key = k
+		r.reacted = false
+		r.action(r)
+		if r.closeScreen {
+			return
+		}
+	}
+	if len(keys) == 0 {
+		r.action(r)
 	}
 }
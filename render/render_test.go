@@ -0,0 +1,178 @@
+package render
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	gol "github.com/tomlockwood/gogol"
+)
+
+func headlessRenderer(frames int) (*Renderer, *Headless, *[]time.Duration) {
+	h := NewHeadless(frames)
+	r := MakeBackend(h, 10)
+	r.SetTicksPerSecond(0)
+	r.InitGame(gol.Options{X: 6, Y: 4, RuleNumber: 2})
+	sleeps := &[]time.Duration{}
+	r.sleep = func(d time.Duration) { *sleeps = append(*sleeps, d) }
+	return r, h, sleeps
+}
+
+func TestRenderHeadless(t *testing.T) {
+	r, h, sleeps := headlessRenderer(5)
+	r.Render()
+
+	if len(h.Frames) != 5 || !h.Closed() {
+		t.Fatalf("Expected 5 frames and a closed backend, got %d frames", len(h.Frames))
+	}
+	if len(h.Frames[0]) != 4 || len(h.Frames[0][0]) != 6 {
+		t.Fatalf("Frame does not match game size")
+	}
+	if r.Game().Ticks() != 5 {
+		t.Fatalf("Expected a tick per frame, got %d ticks", r.Game().Ticks())
+	}
+
+	// Every frame waits for the rest of its tenth of a second
+	for _, d := range *sleeps {
+		if d <= 0 || d > time.Second/10 {
+			t.Fatalf("Expected sleeps within a frame at 10 fps, got %v", d)
+		}
+	}
+}
+
+func TestRenderKeys(t *testing.T) {
+	r, h, _ := headlessRenderer(0)
+	h.Press(2, KeyR)
+	h.Press(4, KeyEscape)
+	r.Render()
+
+	if len(h.Frames) != 4 {
+		t.Fatalf("Expected escape before the fifth frame to close, got %d frames", len(h.Frames))
+	}
+	if r.Game().Ticks() != 2 {
+		t.Fatalf("Expected reset on the third frame, got %d ticks", r.Game().Ticks())
+	}
+}
+
+func TestRenderersIndependent(t *testing.T) {
+	first, firstBackend, _ := headlessRenderer(0)
+	second, secondBackend, _ := headlessRenderer(3)
+	firstBackend.Press(1, KeyEscape)
+
+	var actions int
+	second.SetActionFunction(func(r *Renderer) {
+		actions++
+		ActionDefault(r)
+	})
+
+	first.Render()
+	second.Render()
+
+	if len(firstBackend.Frames) != 1 || len(secondBackend.Frames) != 3 {
+		t.Fatalf("Closing one renderer affected the other")
+	}
+	if actions != 3 || first.Game() == second.Game() {
+		t.Fatalf("Renderers share state")
+	}
+}
+
+func TestNewGameKeepsOptions(t *testing.T) {
+	r, h, _ := headlessRenderer(3)
+	grid := [][]uint8{{0, 0, 0}, {0, 1, 0}, {0, 0, 0}}
+	r.InitGame(gol.Options{Grid: grid, Rules: gol.Rules{Array: []gol.Rule{{}, {Alive: true}}}})
+	// The lone cell dies on the first tick, then the new game is paused
+	h.Press(2, KeySpace, 'P')
+	r.Render()
+
+	if grid[1][1] != 1 {
+		t.Fatalf("Ticking changed the grid in the options")
+	}
+	if r.Game().Ticks() != 0 || r.Game().Field.Front[1][1] != 1 {
+		t.Fatalf("New game did not start from the grid in the options")
+	}
+}
+
+func TestRenderErrorHandler(t *testing.T) {
+	r, h, _ := headlessRenderer(2)
+	r.SetSaveDir(filepath.Join(t.TempDir(), "missing"))
+	var errs []error
+	r.SetErrorHandler(func(err error) { errs = append(errs, err) })
+	h.Press(0, KeyK)
+	r.Render()
+
+	if len(errs) != 1 {
+		t.Fatalf("Expected the failed save to be handled, got %v", errs)
+	}
+}
+
+func TestRenderPauseStep(t *testing.T) {
+	r, h, _ := headlessRenderer(6)
+	h.Press(1, KeyP)
+	h.Press(3, KeyN, KeyN)
+	h.Press(4, KeyP)
+	r.Render()
+
+	// Ticks on frame 0, pauses, steps twice on frame 3, resumes for frames 4 and 5
+	if r.Game().Ticks() != 5 {
+		t.Fatalf("Expected 5 ticks, got %d", r.Game().Ticks())
+	}
+}
+
+func TestRenderSpeed(t *testing.T) {
+	r, h, sleeps := headlessRenderer(3)
+	h.Press(1, KeyEqual, KeyEqual)
+	h.Press(2, KeyRightBracket)
+	r.Render()
+
+	if r.TicksPerFrame() != 4 || r.Game().Ticks() != 9 {
+		t.Fatalf("Expected 4 ticks per frame and 9 ticks, got %d and %d", r.TicksPerFrame(), r.Game().Ticks())
+	}
+	if r.FPS() != 20 || (*sleeps)[2] > time.Second/20 {
+		t.Fatalf("Expected the last frame at 20 fps, got %d", r.FPS())
+	}
+
+	r.SetTicksPerFrame(0)
+	if r.TicksPerFrame() != 1 {
+		t.Fatalf("Ticks per frame below 1")
+	}
+}
+
+func TestRenderRewind(t *testing.T) {
+	r, h, _ := headlessRenderer(4)
+	r.SetHistory(2)
+	h.Press(3, KeyComma, KeyComma, KeyComma)
+	r.Render()
+
+	if r.Rewind() || r.Game().Ticks() != 1 || !r.Paused() {
+		t.Fatalf("Expected rewinding two ticks to tick 1, got tick %d", r.Game().Ticks())
+	}
+}
+
+func TestParseBindings(t *testing.T) {
+	b, err := ParseBindings(map[string]string{"space": "pause", "escape": "none", "q": "quit"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b[KeyEscape]; ok {
+		t.Fatalf("Expected escape to be unbound")
+	}
+
+	r, h, _ := headlessRenderer(0)
+	r.SetBindings(b)
+	h.Press(1, KeySpace)
+	h.Press(2, KeyQ)
+	r.Render()
+	if !r.Paused() || len(h.Frames) != 2 {
+		t.Fatalf("Remapped keys not used")
+	}
+
+	if _, err := ParseBindings(map[string]string{"P": "fly"}); err == nil {
+		t.Fatalf("Expected error for unknown command")
+	}
+	if _, err := ParseKey("Hyper"); err == nil {
+		t.Fatalf("Expected error for unknown key")
+	}
+	if KeyEscape.String() != "Escape" || KeyK.String() != "K" {
+		t.Fatalf("Key names do not round trip")
+	}
+}
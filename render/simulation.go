@@ -0,0 +1,182 @@
+package render
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	gol "github.com/tomlockwood/gogol"
+)
+
+// Unlimited ticks per second runs the simulation as fast as it can
+const Unlimited = -1
+
+// snapshot is a double buffered copy of the game for drawing. The
+// simulation writes the back buffer and the draw loop swaps it to
+// the front, which only the draw loop reads
+type snapshot struct {
+	mu          sync.Mutex
+	front, back gol.Game
+	ticks       [2]int
+	fresh       bool
+	wanted      bool
+}
+
+// write copies a game into the back buffer, if force is false
+// only when the draw loop has taken the last copy
+func (s *snapshot) write(g *gol.Game, force bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !force && !s.wanted {
+		return
+	}
+	copyGame(&s.back, g)
+	s.ticks[1] = g.Ticks()
+	s.fresh, s.wanted = true, false
+}
+
+// want is true when the draw loop has taken the last copy
+func (s *snapshot) want() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.wanted
+}
+
+// read swaps in the latest copy of the game, returning it and its tick
+func (s *snapshot) read() (*gol.Game, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fresh {
+		s.front, s.back = s.back, s.front
+		s.ticks[0], s.ticks[1] = s.ticks[1], s.ticks[0]
+		s.fresh = false
+	}
+	s.wanted = true
+	return &s.front, s.ticks[0]
+}
+
+// copyGame copies the size, rules and field of a game, reusing dst's field
+func copyGame(dst *gol.Game, g *gol.Game) {
+	if dst.X != g.X || dst.Y != g.Y {
+		dst.Field = gol.GridBuffers{X: g.X, Y: g.Y, Front: gol.MakeGrid(g.X, g.Y)}
+	}
+	dst.X, dst.Y = g.X, g.Y
+	dst.Rules.Array = append(dst.Rules.Array[:0], g.Rules.Array...)
+	for y := range g.Field.Front {
+		copy(dst.Field.Front[y], g.Field.Front[y])
+	}
+}
+
+// TicksPerSecond is the simulation's target rate, 0 when it ticks with each frame
+func (r *Renderer) TicksPerSecond() float64 {
+	return r.tps
+}
+
+// SetTicksPerSecond runs the simulation on its own goroutine at a target rate,
+// or Unlimited. A rate of 0 ticks TicksPerFrame times with each frame instead
+func (r *Renderer) SetTicksPerSecond(tps float64) {
+	if tps < 0 {
+		tps = Unlimited
+	}
+	r.tps = tps
+	r.wakeSimulation()
+}
+
+// Faster doubles the ticks per second, or the ticks per frame when they are tied
+func (r *Renderer) Faster() {
+	if r.tps > 0 {
+		r.SetTicksPerSecond(r.tps * 2)
+	} else if r.tps == 0 {
+		r.SetTicksPerFrame(r.ticksPerFrame * 2)
+	}
+}
+
+// Slower halves the ticks per second, or the ticks per frame when they are tied
+func (r *Renderer) Slower() {
+	if r.tps > 0 {
+		r.SetTicksPerSecond(r.tps / 2)
+	} else if r.tps == 0 {
+		r.SetTicksPerFrame(r.ticksPerFrame / 2)
+	}
+}
+
+// wakeSimulation tells the simulation its controls changed
+func (r *Renderer) wakeSimulation() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// simulate ticks the game at the target rate until stop is closed,
+// holding the game lock for a single tick at a time so the draw
+// loop is never kept waiting for longer than one tick
+func (r *Renderer) simulate(stop <-chan struct{}) {
+	var start time.Time
+	var rate float64
+	var done int
+	wait := func(d time.Duration) bool {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-stop:
+			return false
+		case <-r.wake:
+		case <-timer.C:
+		}
+		return true
+	}
+
+	for {
+		r.mu.Lock()
+		tps, running := r.tps, !r.paused || r.steps > 0
+		if tps != 0 && r.steps > 0 {
+			r.steps--
+			r.tick()
+			r.publish(false)
+			r.mu.Unlock()
+			continue
+		}
+		r.mu.Unlock()
+
+		if tps == 0 || !running {
+			start = time.Time{}
+			if !wait(time.Second) {
+				return
+			}
+			continue
+		}
+
+		// Pace against the time the current rate started,
+		// dropping ticks that fall more than a second behind
+		if start.IsZero() || rate != tps {
+			start, rate, done = time.Now(), tps, 0
+		}
+		if tps > 0 {
+			due := int(time.Since(start).Seconds() * tps)
+			if behind := maxInt(1, int(math.Ceil(tps))); due-done > behind {
+				done = due - behind
+			}
+			if due <= done {
+				next := start.Add(time.Duration(float64(done+1) / tps * float64(time.Second)))
+				if !wait(time.Until(next)) {
+					return
+				}
+				continue
+			}
+		}
+
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		r.mu.Lock()
+		if !r.paused && r.tps != 0 {
+			r.tick()
+			r.publish(false)
+		}
+		r.mu.Unlock()
+		done++
+	}
+}
@@ -0,0 +1,111 @@
+package render
+
+import (
+	"testing"
+	"time"
+
+	gol "github.com/tomlockwood/gogol"
+)
+
+func TestSimulationDecoupled(t *testing.T) {
+	h := NewHeadless(6)
+	r := MakeBackend(h, 60)
+	r.InitGame(gol.Options{X: 40, Y: 40, RuleNumber: 3})
+	r.SetTicksPerSecond(Unlimited)
+	r.sleep = func(time.Duration) { time.Sleep(5 * time.Millisecond) }
+	r.Render()
+
+	if len(h.Frames) != 6 {
+		t.Fatalf("Expected 6 frames, got %d", len(h.Frames))
+	}
+	if r.Game().Ticks() <= len(h.Frames) {
+		t.Fatalf("Expected more ticks than frames at an unlimited rate, got %d ticks", r.Game().Ticks())
+	}
+}
+
+func TestSimulationFirstFrame(t *testing.T) {
+	h := NewHeadless(1)
+	r := MakeBackend(h, 60)
+	r.InitGame(gol.Options{X: 8, Y: 8, RuleNumber: 2})
+	r.Zoom(2)
+	camera := r.Camera()
+	r.Render()
+
+	if len(h.Frames[0]) != 8 || h.Cameras[0] != camera {
+		t.Fatalf("Expected the game drawn with the camera set, got %d rows and %v", len(h.Frames[0]), h.Cameras[0])
+	}
+}
+
+func TestSimulationRate(t *testing.T) {
+	h := NewHeadless(10)
+	r := MakeBackend(h, 100)
+	r.InitGame(gol.Options{X: 10, Y: 10, RuleNumber: 2})
+	r.SetTicksPerSecond(200)
+	start := time.Now()
+	r.Render()
+	elapsed := time.Since(start).Seconds()
+
+	// Allow for the time taken to start and stop the simulation
+	if ticks := float64(r.Game().Ticks()); ticks > 200*elapsed+2 {
+		t.Fatalf("Expected at most %v ticks at 200 a second, got %v", 200*elapsed, ticks)
+	}
+}
+
+func TestSimulationSlowRate(t *testing.T) {
+	h := NewHeadless(14)
+	r := MakeBackend(h, 10)
+	r.InitGame(gol.Options{X: 10, Y: 10, RuleNumber: 2})
+	r.SetTicksPerSecond(0.9)
+	r.Render()
+
+	// The first tick is due after 1.1 seconds of the 1.4 rendered
+	if r.Game().Ticks() != 1 {
+		t.Fatalf("Expected 1 tick at 0.9 a second, got %d", r.Game().Ticks())
+	}
+}
+
+func TestSimulationPaused(t *testing.T) {
+	h := NewHeadless(4)
+	r := MakeBackend(h, 100)
+	r.InitGame(gol.Options{X: 10, Y: 10, RuleNumber: 2})
+	r.SetTicksPerSecond(Unlimited)
+	r.SetPaused(true)
+	h.Press(2, KeyR)
+	r.Render()
+
+	if r.Game().Ticks() != 0 {
+		t.Fatalf("Expected a reset paused game, got %d ticks", r.Game().Ticks())
+	}
+	// The reset is drawn even though the simulation is paused
+	for y := range h.Frames[3] {
+		for x := range h.Frames[3][y] {
+			if h.Frames[3][y][x] != r.Game().Cell(x, y) {
+				t.Fatalf("Last frame does not show the reset game")
+			}
+		}
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	var s snapshot
+	g := gol.MakeGame(gol.Options{X: 3, Y: 2, Grid: [][]uint8{{0, 1, 0}, {1, 0, 1}}, RuleNumber: 2})
+
+	s.write(&g, false)
+	if front, _ := s.read(); front.X != 0 {
+		t.Fatalf("Expected no copy before one is wanted")
+	}
+	g.Tick()
+	s.write(&g, false)
+	front, ticks := s.read()
+	if front.X != 3 || ticks != 1 || front.Field.Front[1][2] != g.Cell(2, 1) {
+		t.Fatalf("Expected a copy of the game at tick 1")
+	}
+
+	// The front is not changed by later writes until it is read again
+	drawn := front.Field.Front[0][0]
+	g.Set(0, 0, 1-drawn)
+	s.write(&g, true)
+	if front.Field.Front[0][0] != drawn {
+		t.Fatalf("Write changed the buffer being drawn")
+	}
+}
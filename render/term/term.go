@@ -0,0 +1,373 @@
+// Package term draws a Game in an ANSI terminal, so games
+// can be watched over SSH where OpenGL is not available
+package term
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	gol "github.com/tomlockwood/gogol"
+	"github.com/tomlockwood/gogol/render"
+	"golang.org/x/term"
+)
+
+// Backend is a render.Backend drawing in a terminal, reading keys
+// from In and writing frames to Out. It has no mouse
+type Backend struct {
+	in  io.Reader
+	out io.Writer
+
+	// Width and Height of the frame in cells, when Out is a terminal
+	// they follow its size leaving the last line free
+	Width, Height int
+
+	state   *term.State
+	input   chan []byte
+	done    chan struct{}
+	stopped chan struct{}
+	pending []byte
+	waited  bool
+	hud     [][]render.HUDSpan
+	started bool
+	closed  bool
+	quit    bool
+}
+
+// Make a renderer drawing to standard output and reading
+// keys from standard input, with a game from the options
+func Make(o gol.Options, fps int) (*render.Renderer, error) {
+	b, err := New(os.Stdin, os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	r := render.MakeBackend(b, fps)
+	r.InitGame(o)
+	return r, nil
+}
+
+// New makes a backend, putting In into raw mode if it is a
+// terminal so single key presses are read until it is closed
+func New(in io.Reader, out io.Writer) (*Backend, error) {
+	b := &Backend{
+		in:      in,
+		out:     out,
+		input:   make(chan []byte, 16),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{})}
+	if f, ok := in.(*os.File); ok && term.IsTerminal(fd(f)) {
+		state, err := term.MakeRaw(fd(f))
+		if err != nil {
+			return nil, err
+		}
+		b.state = state
+	}
+	b.resize()
+	go b.read()
+	return b, nil
+}
+
+// Size of the game that fills the terminal, leaving the last line free
+func Size() (x, y int, err error) {
+	return size(os.Stdout)
+}
+
+// size in cells of a terminal, two rows of cells to a line
+func size(f *os.File) (x, y int, err error) {
+	width, height, err := term.GetSize(fd(f))
+	if err != nil {
+		return 0, 0, err
+	}
+	return width, (height - 1) * 2, nil
+}
+
+// fd is the descriptor of a file, without the blocking mode
+// that calling Fd puts it in so reads can still be interrupted
+func fd(f *os.File) int {
+	n := -1
+	if c, err := f.SyscallConn(); err == nil {
+		c.Control(func(d uintptr) { n = int(d) })
+	}
+	return n
+}
+
+// resize the frame to fit Out if it is a terminal
+func (b *Backend) resize() {
+	if f, ok := b.out.(*os.File); ok {
+		if x, y, err := size(f); err == nil && x > 0 && y > 0 {
+			b.Width, b.Height = x, y
+		}
+	}
+}
+
+// read In until it ends or the backend is closed
+func (b *Backend) read() {
+	defer close(b.stopped)
+	defer close(b.input)
+	for {
+		buf := make([]byte, 64)
+		n, err := b.in.Read(buf)
+		if n > 0 {
+			select {
+			case b.input <- buf[:n]:
+			case <-b.done:
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Poll returns the keys read since it was last called
+func (b *Backend) Poll() []render.Key {
+	read := false
+drain:
+	for {
+		select {
+		case data, ok := <-b.input:
+			if !ok {
+				b.quit = true
+				break drain
+			}
+			b.pending = append(b.pending, data...)
+			read = true
+		default:
+			break drain
+		}
+	}
+	// An escape with nothing after it by the next frame is the escape key
+	var keys []render.Key
+	keys, b.pending = parseKeys(b.pending, b.waited && !read)
+	b.waited = len(b.pending) > 0
+	for _, k := range keys {
+		if k == keyCtrlC {
+			b.quit = true
+			return nil
+		}
+	}
+	return keys
+}
+
+// keyCtrlC is read as a byte in raw mode instead of interrupting
+const keyCtrlC render.Key = -3
+
+// parseKeys reads the keys in data, returning any escape sequence
+// left incomplete at the end. If flush is true an incomplete
+// sequence is read as the escape key instead
+func parseKeys(data []byte, flush bool) (keys []render.Key, rest []byte) {
+	for len(data) > 0 {
+		c := data[0]
+		if c != 27 {
+			if k, ok := byteKey(c); ok {
+				keys = append(keys, k)
+			}
+			data = data[1:]
+			continue
+		}
+		k, n := escapeKey(data)
+		if n == 0 {
+			if !flush {
+				return keys, data
+			}
+			k, n = render.KeyEscape, len(data)
+		}
+		if k != render.KeyUnknown {
+			keys = append(keys, k)
+		}
+		data = data[n:]
+	}
+	return keys, nil
+}
+
+// byteKey is the key of a single byte, false for control bytes with no key
+func byteKey(c byte) (render.Key, bool) {
+	switch {
+	case c == 3:
+		return keyCtrlC, true
+	case c == '\r' || c == '\n':
+		return render.KeyEnter, true
+	case c == '\t':
+		return render.KeyTab, true
+	case c == 127 || c == 8:
+		return render.KeyBackspace, true
+	case c >= 'a' && c <= 'z':
+		return render.Key(c - 'a' + 'A'), true
+	case c >= ' ' && c < 127:
+		return render.Key(c), true
+	}
+	return render.KeyUnknown, false
+}
+
+// escapeKeys are the keys sent as escape sequences, by the
+// sequence after the escape and [ or O
+var escapeKeys = map[string]render.Key{
+	"A":  render.KeyUp,
+	"B":  render.KeyDown,
+	"C":  render.KeyRight,
+	"D":  render.KeyLeft,
+	"H":  render.KeyHome,
+	"1~": render.KeyHome,
+	"7~": render.KeyHome,
+	"3~": render.KeyDelete,
+	"5~": render.KeyPageUp,
+	"6~": render.KeyPageDown,
+}
+
+// escapeKey reads the key of the escape sequence at the start of data
+// and its length, 0 if the sequence is incomplete. Sequences of keys
+// with no binding, and escapes pressed with another key, are unknown
+func escapeKey(data []byte) (render.Key, int) {
+	if len(data) < 2 {
+		return render.KeyUnknown, 0
+	}
+	switch data[1] {
+	case 27:
+		return render.KeyEscape, 1
+	case 'O':
+		if len(data) < 3 {
+			return render.KeyUnknown, 0
+		}
+		if k, ok := escapeKeys[string(data[2])]; ok {
+			return k, 3
+		}
+		return render.KeyUnknown, 3
+	case '[':
+		// Parameters and intermediates run up to a final byte from @ to ~
+		for n := 2; n < len(data); n++ {
+			if data[n] >= '@' && data[n] <= '~' {
+				if k, ok := escapeKeys[string(data[2:n+1])]; ok {
+					return k, n + 1
+				}
+				return render.KeyUnknown, n + 1
+			}
+			if data[n] < ' ' || data[n] > '?' {
+				return render.KeyUnknown, n
+			}
+		}
+		return render.KeyUnknown, 0
+	}
+	return render.KeyUnknown, 2
+}
+
+// Mouse is always released outside of the field, the terminal has no mouse
+func (b *Backend) Mouse() render.Mouse {
+	return render.Mouse{}
+}
+
+// ShowHUD sets the lines drawn under the next frames, nil hides the HUD
+func (b *Backend) ShowHUD(lines [][]render.HUDSpan) {
+	b.hud = lines
+}
+
+// Draw the part of the game the camera shows, hiding the
+// cursor and clearing the screen before the first frame
+func (b *Backend) Draw(g *gol.Game, c render.Camera) {
+	if !b.started {
+		b.started = true
+		fmt.Fprint(b.out, "\x1b[?25l\x1b[2J")
+	}
+	b.resize()
+	width, height := b.Width, b.Height
+	if width < 1 || height < 1 {
+		width, height = g.X, g.Y
+	}
+	var s strings.Builder
+	s.WriteString("\x1b[H")
+	s.WriteString(Frame(g, c, width, height))
+	for _, line := range b.hud {
+		for _, span := range line {
+			s.WriteString(colour(38, span.Colour))
+			s.WriteString(span.Text)
+		}
+		s.WriteString("\x1b[0m\x1b[K\r\n")
+	}
+	s.WriteString("\x1b[J")
+	io.WriteString(b.out, s.String())
+}
+
+// ShouldClose once Ctrl-C is pressed or In ends
+func (b *Backend) ShouldClose() bool {
+	return b.quit
+}
+
+// Close restores the terminal and stops reading In, the backend
+// cannot be used after. A read already waiting on In that cannot
+// be interrupted ends with the next key pressed
+func (b *Backend) Close() {
+	if b.closed {
+		return
+	}
+	b.closed = true
+	close(b.done)
+	if d, ok := b.in.(interface{ SetReadDeadline(time.Time) error }); ok {
+		d.SetReadDeadline(time.Now())
+	}
+	if f, ok := b.in.(*os.File); ok && b.state != nil {
+		term.Restore(fd(f), b.state)
+	}
+	if b.started {
+		fmt.Fprint(b.out, "\x1b[0m\x1b[?25h\r\n")
+	}
+}
+
+// Frame draws the part of a game the camera shows as text, scaled
+// to width by height cells. Two rows of cells are drawn on each line
+// using the upper half block coloured by the top cell over a
+// background coloured by the bottom cell. Like the OpenGL renderer the
+// first row of the game is at the bottom
+func Frame(g *gol.Game, c render.Camera, width, height int) string {
+	v := c.View(g.X, g.Y)
+	cell := func(x, y int) gol.Colour {
+		cx := int(math.Floor(v.Left + (float64(x)+0.5)/float64(width)*v.Width))
+		cy := int(math.Floor(v.Bottom + (float64(y)+0.5)/float64(height)*v.Height))
+		cx = clamp(cx, 0, g.X-1)
+		cy = clamp(cy, 0, g.Y-1)
+		return g.Rules.Array[g.Field.Front[cy][cx]].Colour
+	}
+
+	var b strings.Builder
+	for y := height - 1; y >= 0; y -= 2 {
+		var fg, bg string
+		for x := 0; x < width; x++ {
+			top := colour(38, cell(x, y))
+			bottom := "\x1b[49m"
+			if y > 0 {
+				bottom = colour(48, cell(x, y-1))
+			}
+			// Only write colours that change from the previous cell
+			if top != fg {
+				b.WriteString(top)
+				fg = top
+			}
+			if bottom != bg {
+				b.WriteString(bottom)
+				bg = bottom
+			}
+			b.WriteString("▀")
+		}
+		b.WriteString("\x1b[0m\r\n")
+	}
+	return b.String()
+}
+
+// clamp n between min and max
+func clamp(n, min, max int) int {
+	if n < min {
+		return min
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
+
+// colour is the 24 bit escape code for a foreground (38) or background (48)
+func colour(layer int, c gol.Colour) string {
+	n := c.NRGBA()
+	return fmt.Sprintf("\x1b[%d;2;%d;%d;%dm", layer, n.R, n.G, n.B)
+}
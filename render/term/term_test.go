@@ -0,0 +1,103 @@
+package term
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	gol "github.com/tomlockwood/gogol"
+	"github.com/tomlockwood/gogol/render"
+)
+
+func TestFrame(t *testing.T) {
+	rules := gol.Rules{Array: []gol.Rule{
+		{Colour: gol.Colour{R: 0, G: 0, B: 0}},
+		{Alive: true, Colour: gol.Colour{R: 1, G: 0, B: 0}}}}
+	g := gol.MakeGame(gol.Options{X: 2, Y: 3, Grid: [][]uint8{{1, 0}, {0, 0}, {1, 1}}, RuleNumber: 2, Rules: rules})
+
+	lines := strings.Split(Frame(&g, render.MakeCamera(2, 3), 2, 3), "\r\n")
+	if len(lines) != 3 || lines[2] != "" {
+		t.Fatalf("Expected two lines for three rows, got %q", lines)
+	}
+
+	// The last row of the game is at the top
+	expected := "\x1b[38;2;255;0;0m\x1b[48;2;0;0;0m▀▀\x1b[0m"
+	if lines[0] != expected {
+		t.Fatalf("Expected first line %q, got %q", expected, lines[0])
+	}
+
+	// The first row of an odd height game has no cells below it
+	expected = "\x1b[38;2;255;0;0m\x1b[49m▀\x1b[38;2;0;0;0m▀\x1b[0m"
+	if lines[1] != expected {
+		t.Fatalf("Expected last line %q, got %q", expected, lines[1])
+	}
+
+	// Zoomed into the bottom left cell, it fills the frame
+	c := render.Camera{X: 0.5, Y: 0.5, Zoom: 3}
+	expected = "\x1b[38;2;255;0;0m\x1b[48;2;255;0;0m▀▀▀\x1b[0m\r\n"
+	if got := Frame(&g, c, 3, 2); got != expected {
+		t.Fatalf("Expected zoomed frame %q, got %q", expected, got)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, rest := parseKeys([]byte("q \x1b[A\x1b[5~\x1bOD\r\x1b[1;5C\x1b\x1b"), false)
+	expected := []render.Key{render.KeyQ, render.KeySpace, render.KeyUp, render.KeyPageUp,
+		render.KeyLeft, render.KeyEnter, render.KeyEscape}
+	if len(keys) != len(expected) {
+		t.Fatalf("Expected keys %v, got %v", expected, keys)
+	}
+	for idx := range keys {
+		if keys[idx] != expected[idx] {
+			t.Fatalf("Expected keys %v, got %v", expected, keys)
+		}
+	}
+
+	// An escape may be the start of a sequence until the next poll
+	if string(rest) != "\x1b" {
+		t.Fatalf("Expected a trailing escape to wait, got %q", rest)
+	}
+	keys, rest = parseKeys(rest, true)
+	if len(keys) != 1 || keys[0] != render.KeyEscape || len(rest) != 0 {
+		t.Fatalf("Expected the escape key once flushed, got %v and %q", keys, rest)
+	}
+}
+
+func TestBackendRender(t *testing.T) {
+	in, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	defer w.Close()
+	var out bytes.Buffer
+	b, err := New(in, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Arrow keys pan the camera and do not quit, Ctrl-C does
+	r := render.MakeBackend(b, 1000)
+	r.InitGame(gol.Options{X: 8, Y: 8, RuleNumber: 2})
+	w.Write([]byte("pz\x1b[C"))
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte{3})
+	}()
+	r.Render()
+
+	if !r.Paused() || r.Camera().Zoom <= 1 || r.Camera().X <= 4 {
+		t.Fatalf("Expected the game paused and zoomed in on the right, got %v", r.Camera())
+	}
+	if !strings.Contains(out.String(), "▀") {
+		t.Fatalf("Expected frames drawn")
+	}
+
+	select {
+	case <-b.stopped:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected closing to stop reading the input")
+	}
+}
@@ -0,0 +1,42 @@
+package render
+
+import gol "github.com/tomlockwood/gogol"
+
+// paletteSize is the width of the palette texture, one texel for every possible rule
+const paletteSize = 256
+
+// indexBuffer flattens the cells from x0, y0 up to x1, y1 into one rule
+// index per cell, row by row, reusing dst when it is large enough. The
+// first row is the bottom of the texture, which draws it at the bottom
+// of the screen
+func indexBuffer(dst []uint8, g *gol.Game, x0, y0, x1, y1 int) []uint8 {
+	width := x1 - x0
+	if cap(dst) < width*(y1-y0) {
+		dst = make([]uint8, width*(y1-y0))
+	}
+	dst = dst[:width*(y1-y0)]
+	for y := y0; y < y1; y++ {
+		copy(dst[(y-y0)*width:(y-y0+1)*width], g.Field.Front[y][x0:x1])
+	}
+	return dst
+}
+
+// paletteBuffer is the RGB colour of each rule, padded with black
+// to the size of the palette texture
+func paletteBuffer(dst []uint8, rules gol.Rules) []uint8 {
+	if cap(dst) < paletteSize*3 {
+		dst = make([]uint8, paletteSize*3)
+	}
+	dst = dst[:paletteSize*3]
+	for idx := range dst {
+		dst[idx] = 0
+	}
+	for idx, ru := range rules.Array {
+		if idx == paletteSize {
+			break
+		}
+		c := ru.Colour.NRGBA()
+		dst[idx*3], dst[idx*3+1], dst[idx*3+2] = c.R, c.G, c.B
+	}
+	return dst
+}
@@ -0,0 +1,44 @@
+package render
+
+import (
+	"testing"
+
+	gol "github.com/tomlockwood/gogol"
+)
+
+func TestIndexBuffer(t *testing.T) {
+	g := gol.MakeGame(gol.Options{X: 3, Y: 2, Grid: [][]uint8{{0, 1, 2}, {2, 1, 0}}, RuleNumber: 3})
+	buf := indexBuffer(nil, &g, 0, 0, 3, 2)
+	expected := []uint8{0, 1, 2, 2, 1, 0}
+	for idx := range expected {
+		if buf[idx] != expected[idx] {
+			t.Fatalf("Expected %v, got %v", expected, buf)
+		}
+	}
+
+	reused := indexBuffer(make([]uint8, 0, 10), &g, 1, 0, 3, 2)
+	if len(reused) != 4 || cap(reused) != 10 || reused[0] != 1 || reused[2] != 1 {
+		t.Fatalf("Expected the visible cells in a reused buffer, got %v", reused)
+	}
+}
+
+func TestPaletteBuffer(t *testing.T) {
+	rules := gol.Rules{Array: []gol.Rule{
+		{Colour: gol.Colour{R: 0, G: 0, B: 0}},
+		{Colour: gol.Colour{R: 1, G: 0.5, B: 0}}}}
+	buf := paletteBuffer(nil, rules)
+	if len(buf) != paletteSize*3 {
+		t.Fatalf("Expected a palette of %d colours, got %d bytes", paletteSize, len(buf))
+	}
+	if buf[3] != 255 || buf[4] != 128 || buf[5] != 0 || buf[6] != 0 {
+		t.Fatalf("Palette does not match rule colours, got %v", buf[:9])
+	}
+}
+
+func BenchmarkIndexBuffer(b *testing.B) {
+	g := gol.MakeGame(gol.Options{X: 500, Y: 500, RuleNumber: 4})
+	var buf []uint8
+	for n := 0; n < b.N; n++ {
+		buf = indexBuffer(buf, &g, 0, 0, g.X, g.Y)
+	}
+}
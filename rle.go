@@ -0,0 +1,290 @@
+package gol
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// rleLineLength is the longest line written to an RLE file
+const rleLineLength = 70
+
+// LoadRLE loads a pattern from an RLE file
+func LoadRLE(Filename string) (Options, error) {
+	return loadWith(DecodeRLE, Filename, 0)
+}
+
+// SaveRLE saves a game to an RLE file
+func SaveRLE(G SaveContent, Filename string) error {
+	return saveWith(EncodeRLE, G, Filename)
+}
+
+// DecodeRLE reads a two-state or multi-state RLE pattern. The rule
+// in the header is converted with ParseRuleString, and defaults to
+// Conway's Game of Life if missing
+func DecodeRLE(r io.Reader) (Pattern, error) {
+	p := Pattern{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16*1024*1024)
+
+	x, y := 0, 0
+	rule := ""
+	header := false
+	var body strings.Builder
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !header {
+			if line == "" {
+				continue
+			}
+			if strings.HasPrefix(line, "#") {
+				if len(line) < 2 {
+					continue
+				}
+				text := strings.TrimSpace(line[2:])
+				switch line[1] {
+				case 'N':
+					p.Name = text
+				case 'C', 'c':
+					p.Comments = append(p.Comments, text)
+				}
+				continue
+			}
+			if strings.HasPrefix(line, "x") {
+				var err error
+				x, y, rule, err = parseRLEHeader(line)
+				if err != nil {
+					return p, err
+				}
+				header = true
+				continue
+			}
+			return p, errors.New("RLE header line not found")
+		}
+		body.WriteString(line)
+		if strings.Contains(line, "!") {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return p, err
+	}
+	if !header {
+		return p, errors.New("RLE header line not found")
+	}
+
+	rows, maxState, err := parseRLEBody(body.String())
+	if err != nil {
+		return p, err
+	}
+	for len(rows) > 0 && len(rows[len(rows)-1]) == 0 {
+		rows = rows[:len(rows)-1]
+	}
+
+	if rule == "" {
+		p.Rules = lifeRules()
+	} else if p.Rules, err = ParseRuleString(rule); err != nil {
+		return p, err
+	}
+	if int(maxState) >= len(p.Rules.Array) {
+		return p, fmt.Errorf("RLE uses state %d but rule %q only has %d states", maxState, rule, len(p.Rules.Array))
+	}
+
+	p.Grid, err = patternGrid(rows, x, y)
+	return p, err
+}
+
+// parseRLEHeader reads a line such as x = 3, y = 3, rule = B3/S23
+func parseRLEHeader(line string) (x, y int, rule string, err error) {
+	for _, field := range strings.Split(line, ",") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return 0, 0, "", fmt.Errorf("RLE header field %q is not key = value", field)
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		switch key {
+		case "x":
+			x, err = strconv.Atoi(value)
+		case "y":
+			y, err = strconv.Atoi(value)
+		case "rule":
+			rule = value
+		}
+		if err != nil {
+			return 0, 0, "", fmt.Errorf("RLE header field %q is not a number", field)
+		}
+	}
+	if x < 0 || y < 0 {
+		return 0, 0, "", errors.New("RLE header dimensions cannot be negative")
+	}
+	if err := checkGridSize(x, y); err != nil {
+		return 0, 0, "", err
+	}
+	return x, y, rule, nil
+}
+
+// parseRLEBody decodes the run length encoded cells up to the
+// terminating !, returning ragged rows and the highest state used
+func parseRLEBody(body string) ([][]uint8, uint8, error) {
+	rows := [][]uint8{{}}
+	var maxState uint8
+	// Rows can be ragged, the grid will be as wide as the widest
+	width := 0
+	count := 0
+	prefix := byte(0)
+	for idx := 0; idx < len(body); idx++ {
+		c := body[idx]
+		if c >= '0' && c <= '9' {
+			count = count*10 + int(c-'0')
+			if count > MaxGridCells {
+				return nil, 0, fmt.Errorf("RLE run of %d cells is over the limit of %d cells", count, MaxGridCells)
+			}
+			continue
+		}
+		run := count
+		if run == 0 {
+			run = 1
+		}
+		var state int
+		switch {
+		case c == '!':
+			return rows, maxState, nil
+		case c == '$':
+			if err := checkGridSize(maxInt(width, 1), len(rows)+run); err != nil {
+				return nil, 0, err
+			}
+			for i := 0; i < run; i++ {
+				rows = append(rows, []uint8{})
+			}
+			count = 0
+			continue
+		case c == 'b' || c == '.':
+			state = 0
+		case c == 'o':
+			state = 1
+		case c >= 'p' && c <= 'y':
+			if prefix != 0 {
+				return nil, 0, fmt.Errorf("RLE state prefix %q followed by %q", prefix, c)
+			}
+			prefix = c
+			continue
+		case c >= 'A' && c <= 'X':
+			state = int(c-'A') + 1
+			if prefix != 0 {
+				state += 24 * int(prefix-'p'+1)
+				prefix = 0
+			}
+		default:
+			return nil, 0, fmt.Errorf("RLE contains unknown cell %q", c)
+		}
+		if prefix != 0 {
+			return nil, 0, fmt.Errorf("RLE state prefix %q not followed by a state letter", prefix)
+		}
+		if state > 255 {
+			return nil, 0, fmt.Errorf("RLE state %d is above 255", state)
+		}
+		if uint8(state) > maxState {
+			maxState = uint8(state)
+		}
+		row := &rows[len(rows)-1]
+		width = maxInt(width, len(*row)+run)
+		if err := checkGridSize(width, len(rows)); err != nil {
+			return nil, 0, err
+		}
+		for i := 0; i < run; i++ {
+			*row = append(*row, uint8(state))
+		}
+		count = 0
+	}
+	return nil, 0, errors.New("RLE is missing the terminating !")
+}
+
+// EncodeRLE writes a Pattern as RLE, using two-state b/o cells when
+// there are at most two Rules and multi-state letters otherwise.
+// Rules without a rule string are an error, as RLE cannot hold them
+func EncodeRLE(w io.Writer, p Pattern) error {
+	bw := bufio.NewWriter(w)
+	if p.Name != "" {
+		fmt.Fprintf(bw, "#N %s\n", p.Name)
+	}
+	for _, comment := range p.Comments {
+		fmt.Fprintf(bw, "#C %s\n", comment)
+	}
+	x, y := 0, len(p.Grid)
+	if y > 0 {
+		x = len(p.Grid[0])
+	}
+	rule, ok := p.Rules.RuleString()
+	if !ok && len(p.Rules.Array) > 0 {
+		return errors.New("rules cannot be written as an RLE rule string, save as native JSON or binary, or export them with SaveRuleTable as a .rule file")
+	}
+	fmt.Fprintf(bw, "x = %d, y = %d", x, y)
+	if ok {
+		fmt.Fprintf(bw, ", rule = %s", rule)
+	}
+	bw.WriteString("\n")
+
+	multiState := len(p.Rules.Array) > 2
+	line := 0
+	emit := func(run int, tag string) {
+		token := tag
+		if run > 1 {
+			token = strconv.Itoa(run) + tag
+		}
+		if line > 0 && line+len(token) > rleLineLength {
+			bw.WriteString("\n")
+			line = 0
+		}
+		bw.WriteString(token)
+		line += len(token)
+	}
+
+	pendingRows := 0
+	for _, row := range p.Grid {
+		end := len(row)
+		for end > 0 && row[end-1] == 0 {
+			end--
+		}
+		if end == 0 {
+			pendingRows++
+			continue
+		}
+		if pendingRows > 0 {
+			emit(pendingRows, "$")
+		}
+		for start := 0; start < end; {
+			run := 1
+			for start+run < end && row[start+run] == row[start] {
+				run++
+			}
+			emit(run, rleTag(row[start], multiState))
+			start += run
+		}
+		pendingRows = 1
+	}
+	emit(1, "!")
+	bw.WriteString("\n")
+	return bw.Flush()
+}
+
+// rleTag is the letter(s) representing a cell state
+func rleTag(state uint8, multiState bool) string {
+	if !multiState {
+		if state == 0 {
+			return "b"
+		}
+		return "o"
+	}
+	if state == 0 {
+		return "."
+	}
+	prefix := (int(state) - 1) / 24
+	letter := string(rune('A' + (int(state)-1)%24))
+	if prefix == 0 {
+		return letter
+	}
+	return string(rune('p'+prefix-1)) + letter
+}
@@ -0,0 +1,119 @@
+package gol
+
+// RLE and rulestring testing
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const gliderRLE = `#N Glider
+#C The smallest spaceship
+x = 3, y = 3, rule = B3/S23
+bob$2bo$3o!
+`
+
+func TestDecodeRLE(t *testing.T) {
+	p, err := DecodeRLE(strings.NewReader(gliderRLE))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Name != "Glider" || len(p.Comments) != 1 {
+		t.Fatalf("RLE name and comments not read: %q %v", p.Name, p.Comments)
+	}
+
+	for idx, r := range p.Rules.Array {
+		if r.Alive != rs.Array[idx].Alive || r.Transitions != rs.Array[idx].Transitions {
+			t.Fatalf("B3/S23 does not match Conway's rules")
+		}
+	}
+
+	arrayOut := [][]uint8{{0, 1, 0}, {0, 0, 1}, {1, 1, 1}}
+	if mismatchCheck(arrayOut, p.Grid) {
+		t.Fatalf("Glider not decoded")
+	}
+
+	g := MakeGame(p.Options())
+	if g.X != 3 || g.Y != 3 {
+		t.Fatalf("Game not made from RLE options")
+	}
+}
+
+func TestDecodeRLETooLarge(t *testing.T) {
+	for _, rle := range []string{
+		"x = 2000000000, y = 2000000000\no!\n",
+		"x = 1, y = 1\n2000000000o!\n",
+		"x = 1, y = 1\n99999999999999999999o!\n",
+		"x = 1, y = 1\no2000000000$o!\n",
+		"x = 1, y = 1\n40000000o$o!\n",
+	} {
+		if _, err := DecodeRLE(strings.NewReader(rle)); err == nil {
+			t.Fatalf("Expected an error decoding %q", rle)
+		}
+	}
+}
+
+func TestRLERoundTrip(t *testing.T) {
+	rules, err := ParseRuleString("/2/30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grid := MakeGrid(40, 3)
+	for x := range grid[0] {
+		grid[0][x] = uint8(x % 30)
+	}
+	grid[2][39] = 29
+
+	var buf bytes.Buffer
+	if err := EncodeRLE(&buf, Pattern{Name: "States", Grid: grid, Rules: rules}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "rule = B2/S/C30") || !strings.Contains(buf.String(), "pA") {
+		t.Fatalf("Multi-state RLE not written as expected:\n%s", buf.String())
+	}
+
+	p, err := DecodeRLE(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Rules.Array) != 30 || p.Name != "States" {
+		t.Fatalf("Generations rule not read back")
+	}
+	if mismatchCheck(grid, p.Grid) {
+		t.Fatalf("Multi-state grid not read back")
+	}
+}
+
+func TestRuleString(t *testing.T) {
+	for _, rule := range []string{"23/3", "B3S23", "b3/s23", "S23/B3"} {
+		rules, err := ParseRuleString(rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s, ok := rules.RuleString(); !ok || s != "B3/S23" {
+			t.Fatalf("%s did not parse as Conway's rules, got %s", rule, s)
+		}
+	}
+
+	if _, ok := rs.RuleString(); !ok {
+		t.Fatalf("Conway test rules should have a rulestring")
+	}
+
+	if _, err := ParseRuleString("B9/S23"); err == nil {
+		t.Fatalf("Expected an error for a neighbour count of 9")
+	}
+
+	copyOpts = opts
+	copyOpts.Rules = Rules{}
+	copyOpts.Rules.Randomize(3)
+	copyOpts.Rules.Array[2].Alive = true
+	if _, ok := copyOpts.Rules.RuleString(); ok {
+		t.Fatalf("Rules with an alive dying state have no rulestring")
+	}
+	var buf bytes.Buffer
+	if err := EncodeRLE(&buf, Pattern{Grid: MakeGrid(2, 2), Rules: copyOpts.Rules}); err == nil || buf.Len() != 0 {
+		t.Fatalf("Expected an error encoding rules without a rulestring as RLE")
+	}
+}
@@ -1,10 +1,31 @@
 package gol
 
+import (
+	"fmt"
+	"math"
+)
+
 // Colour values
 type Colour struct {
 	R, G, B float32
 }
 
+// Hex returns the Colour as a #rrggbb string
+func (c Colour) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", channel(c.R), channel(c.G), channel(c.B))
+}
+
+// channel converts a 0-1 colour value to 0-255
+func channel(v float32) uint8 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 1 {
+		return 255
+	}
+	return uint8(v*255 + 0.5)
+}
+
 // Rule with alive status and transitions which
 // represent what the rule changes to based on
 // amount of adjacent alive cells (0-8)
@@ -16,12 +37,17 @@ type Rule struct {
 
 // Randomize a single Rule
 func (ru *Rule) Randomize(RuleAmount int) {
-	ru.Alive = randInt(2) == 0
-	ru.Colour.R = float32(float32(randInt(255)) / 255.0)
-	ru.Colour.G = float32(float32(randInt(255)) / 255.0)
-	ru.Colour.B = float32(float32(randInt(255)) / 255.0)
+	ru.RandomizeWith(globalRand{}, RuleAmount)
+}
+
+// RandomizeWith randomizes a single Rule from a given source
+func (ru *Rule) RandomizeWith(rand Intner, RuleAmount int) {
+	ru.Alive = rand.Intn(2) == 0
+	ru.Colour.R = float32(float32(rand.Intn(255)) / 255.0)
+	ru.Colour.G = float32(float32(rand.Intn(255)) / 255.0)
+	ru.Colour.B = float32(float32(rand.Intn(255)) / 255.0)
 	for idx := range ru.Transitions {
-		ru.Transitions[idx] = uint8(randInt(RuleAmount))
+		ru.Transitions[idx] = uint8(rand.Intn(RuleAmount))
 	}
 }
 
@@ -32,8 +58,78 @@ type Rules struct {
 
 // Randomize an array of Rules
 func (rs *Rules) Randomize(RuleAmount int) {
+	rs.RandomizeWith(globalRand{}, RuleAmount)
+}
+
+// RandomizeWith randomizes an array of Rules from a given source
+func (rs *Rules) RandomizeWith(rand Intner, RuleAmount int) {
 	rs.Array = make([]Rule, RuleAmount)
 	for idx := range rs.Array {
-		rs.Array[idx].Randomize(RuleAmount)
+		rs.Array[idx].RandomizeWith(rand, RuleAmount)
+	}
+}
+
+// DefaultColour for rule idx of RuleAmount rules, rule 0 is
+// black and the rest are spread evenly around the colour wheel
+func DefaultColour(idx int, RuleAmount int) Colour {
+	if idx == 0 {
+		return Colour{}
+	}
+	if RuleAmount <= 2 {
+		return Colour{1, 1, 1}
+	}
+	hue := float32(idx-1) / float32(RuleAmount-1) * 6
+	sector := int(hue)
+	f := hue - float32(sector)
+	switch sector {
+	case 0:
+		return Colour{1, f, 0}
+	case 1:
+		return Colour{1 - f, 1, 0}
+	case 2:
+		return Colour{0, 1, f}
+	case 3:
+		return Colour{0, 1 - f, 1}
+	case 4:
+		return Colour{f, 0, 1}
+	}
+	return Colour{1, 0, 1 - f}
+}
+
+// RuleSpaceSize is the amount of distinct Rules with RuleAmount rules,
+// ok is false if the size does not fit in a uint64
+func RuleSpaceSize(RuleAmount int) (size uint64, ok bool) {
+	size = 1
+	for idx := 0; idx < RuleAmount; idx++ {
+		if size > math.MaxUint64/2 {
+			return 0, false
+		}
+		size *= 2
+		for count := 0; count < 9; count++ {
+			if size > math.MaxUint64/uint64(RuleAmount) {
+				return 0, false
+			}
+			size *= uint64(RuleAmount)
+		}
+	}
+	return size, true
+}
+
+// RulesFromIndex enumerates the rule space, decoding index into
+// the alive state and transitions of RuleAmount rules.
+// Rules are given their DefaultColour
+func RulesFromIndex(RuleAmount int, index uint64) Rules {
+	rs := Rules{make([]Rule, RuleAmount)}
+	for idx := range rs.Array {
+		rs.Array[idx].Alive = index%2 == 1
+		index /= 2
+		rs.Array[idx].Colour = DefaultColour(idx, RuleAmount)
+	}
+	for idx := range rs.Array {
+		for count := range rs.Array[idx].Transitions {
+			rs.Array[idx].Transitions[count] = uint8(index % uint64(RuleAmount))
+			index /= uint64(RuleAmount)
+		}
 	}
+	return rs
 }
@@ -0,0 +1,135 @@
+package gol
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseRuleString converts a Life-like rulestring such as B3/S23 or
+// 23/3, or a Generations rulestring such as B2/S/C3 or /2/3, to Rules.
+// Rule 0 is dead, rule 1 is alive and any further rules are dying
+// states that count as dead neighbours
+func ParseRuleString(rule string) (Rules, error) {
+	rule = strings.ToUpper(strings.ReplaceAll(rule, " ", ""))
+	if !strings.Contains(rule, "/") && strings.HasPrefix(rule, "B") {
+		if idx := strings.Index(rule, "S"); idx > 0 {
+			rule = rule[:idx] + "/" + rule[idx:]
+		}
+	}
+	parts := strings.Split(rule, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Rules{}, fmt.Errorf("rulestring %q is not B/S, S/B or Generations notation", rule)
+	}
+
+	var birth, survive string
+	if strings.HasPrefix(parts[0], "B") && strings.HasPrefix(parts[1], "S") {
+		birth, survive = parts[0][1:], parts[1][1:]
+	} else if strings.HasPrefix(parts[0], "S") && strings.HasPrefix(parts[1], "B") {
+		survive, birth = parts[0][1:], parts[1][1:]
+	} else {
+		survive, birth = parts[0], parts[1]
+	}
+
+	states := 2
+	if len(parts) == 3 {
+		c := strings.TrimLeft(parts[2], "CG")
+		n, err := strconv.Atoi(c)
+		if err != nil || n < 2 || n > 256 {
+			return Rules{}, fmt.Errorf("rulestring %q has invalid state count %q", rule, parts[2])
+		}
+		states = n
+	}
+
+	birthCounts, err := ruleCounts(birth)
+	if err != nil {
+		return Rules{}, err
+	}
+	surviveCounts, err := ruleCounts(survive)
+	if err != nil {
+		return Rules{}, err
+	}
+
+	rs := Rules{make([]Rule, states)}
+	dying := uint8(0)
+	if states > 2 {
+		dying = 2
+	}
+	for count := 0; count < 9; count++ {
+		if birthCounts[count] {
+			rs.Array[0].Transitions[count] = 1
+		}
+		if surviveCounts[count] {
+			rs.Array[1].Transitions[count] = 1
+		} else {
+			rs.Array[1].Transitions[count] = dying
+		}
+	}
+	rs.Array[1].Alive = true
+	for idx := 2; idx < states; idx++ {
+		for count := range rs.Array[idx].Transitions {
+			rs.Array[idx].Transitions[count] = uint8((idx + 1) % states)
+		}
+	}
+	for idx := range rs.Array {
+		rs.Array[idx].Colour = DefaultColour(idx, states)
+	}
+	return rs, nil
+}
+
+func ruleCounts(digits string) ([9]bool, error) {
+	var counts [9]bool
+	for _, d := range digits {
+		if d < '0' || d > '8' {
+			return counts, fmt.Errorf("rulestring neighbour count %q is not 0-8", d)
+		}
+		counts[d-'0'] = true
+	}
+	return counts, nil
+}
+
+// RuleString returns the B/S rulestring of Life-like Rules, or the
+// B/S/C rulestring of Generations Rules. ok is false if the Rules
+// cannot be written as a rulestring
+func (rs Rules) RuleString() (rule string, ok bool) {
+	states := len(rs.Array)
+	if states < 2 || states > 256 || rs.Array[0].Alive || !rs.Array[1].Alive {
+		return "", false
+	}
+	dying := uint8(0)
+	if states > 2 {
+		dying = 2
+	}
+	var birth, survive strings.Builder
+	for count := 0; count < 9; count++ {
+		switch rs.Array[0].Transitions[count] {
+		case 0:
+		case 1:
+			birth.WriteByte(byte('0' + count))
+		default:
+			return "", false
+		}
+		switch rs.Array[1].Transitions[count] {
+		case dying:
+		case 1:
+			survive.WriteByte(byte('0' + count))
+		default:
+			return "", false
+		}
+	}
+	for idx := 2; idx < states; idx++ {
+		if rs.Array[idx].Alive {
+			return "", false
+		}
+		for _, next := range rs.Array[idx].Transitions {
+			if int(next) != (idx+1)%states {
+				return "", false
+			}
+		}
+	}
+	rule = "B" + birth.String() + "/S" + survive.String()
+	if states > 2 {
+		rule += fmt.Sprintf("/C%d", states)
+	}
+	return rule, true
+}
@@ -0,0 +1,620 @@
+package gol
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrNotCountBased is returned when a Golly rule table depends on more
+// than a cell's rule and its amount of alive neighbours, so it cannot
+// be expressed with Transitions
+var ErrNotCountBased = errors.New("rule table is not count based")
+
+// ErrUnsupportedTable is returned for rule tables beyond what can be
+// read, such as rule trees, neighbourhoods other than Moore and more
+// states than can be checked with the table's symmetries
+var ErrUnsupportedTable = errors.New("rule table is not supported")
+
+// Limits on the neighbourhoods checked when importing a rule table,
+// permute tables are checked per multiset of neighbours and other
+// symmetries per ordered neighbourhood
+const (
+	maxPermuteStates = 8
+	maxOrderedStates = 4
+)
+
+// RuleTable is a named set of Rules read from or
+// written to a Golly .rule file
+type RuleTable struct {
+	Name  string
+	Rules Rules
+}
+
+// LoadRuleTable loads a Golly .rule file
+func LoadRuleTable(Filename string) (RuleTable, error) {
+	f, err := os.Open(Filename)
+	if err != nil {
+		return RuleTable{}, err
+	}
+	defer f.Close()
+	return DecodeRuleTable(f)
+}
+
+// SaveRuleTable saves Rules to a Golly .rule file
+func SaveRuleTable(rt RuleTable, Filename string) error {
+	f, err := os.Create(Filename)
+	if err != nil {
+		return err
+	}
+	if err := EncodeRuleTable(f, rt); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// tableTerm is a single entry of a transition,
+// either a state or a variable
+type tableTerm struct {
+	variable string
+	values   []int
+}
+
+type tableTransition struct {
+	centre     tableTerm
+	neighbours [8]tableTerm
+	output     tableTerm
+}
+
+// DecodeRuleTable reads the @RULE, @TABLE and @COLORS sections of a
+// Golly .rule file with a Moore neighbourhood. Rules are alive when
+// they count as a neighbour, which is found by searching for a set of
+// alive states that makes every transition depend only on the count
+func DecodeRuleTable(r io.Reader) (RuleTable, error) {
+	rt := RuleTable{}
+	states := 0
+	symmetries := ""
+	vars := map[string][]int{}
+	var transitions []tableTransition
+	colours := map[int]Colour{}
+
+	section := ""
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "@") {
+			fields := strings.Fields(line)
+			section = fields[0]
+			if section == "@RULE" && len(fields) > 1 {
+				rt.Name = fields[1]
+			}
+			if section == "@TREE" {
+				return rt, fmt.Errorf("%w: rule trees are not supported, only @TABLE", ErrUnsupportedTable)
+			}
+			continue
+		}
+
+		fail := func(format string, a ...interface{}) (RuleTable, error) {
+			return rt, fmt.Errorf("rule table line %d: %s", lineNumber, fmt.Sprintf(format, a...))
+		}
+
+		switch section {
+		case "@TABLE":
+			if kv := strings.SplitN(line, ":", 2); len(kv) == 2 {
+				key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+				switch key {
+				case "n_states":
+					n, err := strconv.Atoi(value)
+					if err != nil || n < 2 || n > 256 {
+						return fail("n_states %q is not 2-256", value)
+					}
+					states = n
+				case "neighborhood":
+					if value != "Moore" {
+						return rt, fmt.Errorf("rule table line %d: %w: neighborhood %s, only Moore", lineNumber, ErrUnsupportedTable, value)
+					}
+				case "symmetries":
+					if _, ok := tableSymmetries[value]; !ok {
+						return fail("unknown symmetries %s", value)
+					}
+					symmetries = value
+				}
+				continue
+			}
+			if strings.HasPrefix(line, "var ") {
+				name, values, err := parseTableVar(line[4:], vars, states)
+				if err != nil {
+					return fail("%v", err)
+				}
+				vars[name] = values
+				continue
+			}
+			t, err := parseTableTransition(line, vars, states)
+			if err != nil {
+				return fail("%v", err)
+			}
+			transitions = append(transitions, t)
+		case "@COLORS":
+			fields := strings.Fields(strings.ReplaceAll(line, ",", " "))
+			if len(fields) != 4 {
+				return fail("colour %q is not state r g b", line)
+			}
+			var values [4]int
+			for idx, field := range fields {
+				v, err := strconv.Atoi(field)
+				if err != nil {
+					return fail("colour %q is not state r g b", line)
+				}
+				values[idx] = v
+			}
+			colours[values[0]] = Colour{float32(values[1]) / 255, float32(values[2]) / 255, float32(values[3]) / 255}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return rt, err
+	}
+	if states == 0 {
+		return rt, errors.New("rule table has no @TABLE with n_states")
+	}
+	if symmetries == "" {
+		symmetries = "none"
+	}
+
+	outputs, err := tableOutputs(states, symmetries, transitions)
+	if err != nil {
+		return rt, err
+	}
+	rt.Rules, err = countBasedRules(states, outputs)
+	if err != nil {
+		return rt, err
+	}
+	for idx := range rt.Rules.Array {
+		if c, ok := colours[idx]; ok {
+			rt.Rules.Array[idx].Colour = c
+		} else {
+			rt.Rules.Array[idx].Colour = DefaultColour(idx, states)
+		}
+	}
+	return rt, nil
+}
+
+func parseTableVar(definition string, vars map[string][]int, states int) (string, []int, error) {
+	kv := strings.SplitN(definition, "=", 2)
+	if len(kv) != 2 {
+		return "", nil, fmt.Errorf("var %q is not name={values}", definition)
+	}
+	name := strings.TrimSpace(kv[0])
+	body := strings.TrimSpace(kv[1])
+	if !strings.HasPrefix(body, "{") || !strings.HasSuffix(body, "}") {
+		return "", nil, fmt.Errorf("var %s values are not in braces", name)
+	}
+	var values []int
+	for _, item := range strings.Split(body[1:len(body)-1], ",") {
+		term, err := parseTableTerm(strings.TrimSpace(item), vars, states)
+		if err != nil {
+			return "", nil, err
+		}
+		values = append(values, term.values...)
+	}
+	return name, values, nil
+}
+
+func parseTableTerm(item string, vars map[string][]int, states int) (tableTerm, error) {
+	if values, ok := vars[item]; ok {
+		return tableTerm{item, values}, nil
+	}
+	n, err := strconv.Atoi(item)
+	if err != nil {
+		return tableTerm{}, fmt.Errorf("%q is not a state or defined variable", item)
+	}
+	if n < 0 || n >= states {
+		return tableTerm{}, fmt.Errorf("state %d is outside n_states", n)
+	}
+	return tableTerm{values: []int{n}}, nil
+}
+
+func parseTableTransition(line string, vars map[string][]int, states int) (tableTransition, error) {
+	var items []string
+	if strings.Contains(line, ",") {
+		items = strings.Split(line, ",")
+	} else {
+		for _, c := range line {
+			items = append(items, string(c))
+		}
+	}
+	if len(items) != 10 {
+		return tableTransition{}, fmt.Errorf("transition %q does not have 10 Moore entries", line)
+	}
+	var terms [10]tableTerm
+	for idx, item := range items {
+		term, err := parseTableTerm(strings.TrimSpace(item), vars, states)
+		if err != nil {
+			return tableTransition{}, err
+		}
+		terms[idx] = term
+	}
+	t := tableTransition{centre: terms[0], output: terms[9]}
+	copy(t.neighbours[:], terms[1:9])
+	if t.output.variable != "" && len(t.output.values) > 1 {
+		bound := t.centre.variable == t.output.variable
+		for _, n := range t.neighbours {
+			bound = bound || n.variable == t.output.variable
+		}
+		if !bound {
+			return tableTransition{}, fmt.Errorf("output variable %s is not bound", t.output.variable)
+		}
+	}
+	return t, nil
+}
+
+// tableSymmetries are the orderings of the N, NE, E, SE, S, SW, W, NW
+// neighbours a transition applies to, nil means any permutation
+var tableSymmetries = map[string][][8]int{
+	"none":               symmetryOrders(1, 1, false),
+	"rotate4":            symmetryOrders(4, 2, false),
+	"rotate8":            symmetryOrders(8, 1, false),
+	"reflect_horizontal": symmetryOrders(1, 1, true),
+	"rotate4reflect":     symmetryOrders(4, 2, true),
+	"rotate8reflect":     symmetryOrders(8, 1, true),
+	"permute":            nil,
+}
+
+func symmetryOrders(rotations, step int, reflect bool) [][8]int {
+	var orders [][8]int
+	for r := 0; r < rotations; r++ {
+		var order [8]int
+		for idx := range order {
+			order[idx] = (idx + r*step) % 8
+		}
+		orders = append(orders, order)
+		if reflect {
+			var reflected [8]int
+			for idx := range reflected {
+				reflected[idx] = order[(8-idx)%8]
+			}
+			orders = append(orders, reflected)
+		}
+	}
+	return orders
+}
+
+// match reports whether a term accepts a value, binding variables
+func (term tableTerm) match(value int, bindings map[string]int) (bool, func()) {
+	if term.variable != "" {
+		if bound, ok := bindings[term.variable]; ok {
+			return bound == value, func() {}
+		}
+	}
+	found := false
+	for _, v := range term.values {
+		found = found || v == value
+	}
+	if !found {
+		return false, func() {}
+	}
+	if term.variable == "" {
+		return true, func() {}
+	}
+	bindings[term.variable] = value
+	return true, func() { delete(bindings, term.variable) }
+}
+
+// apply returns the output of a transition for a centre and ordered
+// neighbours, or -1 if it does not match under the given orders
+func (t tableTransition) apply(centre int, neighbours [8]int, orders [][8]int) int {
+	bindings := map[string]int{}
+	ok, _ := t.centre.match(centre, bindings)
+	if !ok {
+		return -1
+	}
+	output := func() int {
+		if t.output.variable != "" {
+			if v, ok := bindings[t.output.variable]; ok {
+				return v
+			}
+		}
+		return t.output.values[0]
+	}
+
+	if orders == nil {
+		used := [8]bool{}
+		var assign func(position int) bool
+		assign = func(position int) bool {
+			if position == 8 {
+				return true
+			}
+			for idx, value := range neighbours {
+				if used[idx] {
+					continue
+				}
+				ok, undo := t.neighbours[position].match(value, bindings)
+				if !ok {
+					continue
+				}
+				used[idx] = true
+				if assign(position + 1) {
+					return true
+				}
+				used[idx] = false
+				undo()
+			}
+			return false
+		}
+		if assign(0) {
+			return output()
+		}
+		return -1
+	}
+
+	for _, order := range orders {
+		var undos []func()
+		matched := true
+		for position, idx := range order {
+			ok, undo := t.neighbours[position].match(neighbours[idx], bindings)
+			undos = append(undos, undo)
+			if !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return output()
+		}
+		for idx := len(undos) - 1; idx >= 0; idx-- {
+			undos[idx]()
+		}
+	}
+	return -1
+}
+
+// tableOutput is the next state of a centre state with
+// the given amount of neighbours in each state
+type tableOutput struct {
+	centre int
+	counts []int
+	output int
+}
+
+// tableOutputs evaluates the table for every neighbourhood, cells
+// with no matching transition keep their state as in Golly
+func tableOutputs(states int, symmetries string, transitions []tableTransition) ([]tableOutput, error) {
+	orders := tableSymmetries[symmetries]
+	if orders == nil && states > maxPermuteStates {
+		return nil, fmt.Errorf("%w: permute rule tables with more than %d states", ErrUnsupportedTable, maxPermuteStates)
+	}
+	if orders != nil && states > maxOrderedStates {
+		return nil, fmt.Errorf("%w: %s rule tables with more than %d states", ErrUnsupportedTable, symmetries, maxOrderedStates)
+	}
+
+	var outputs []tableOutput
+	seen := map[string]int{}
+	var neighbours [8]int
+	var visit func(position, from int) error
+	visit = func(position, from int) error {
+		if position < 8 {
+			start := 0
+			if orders == nil {
+				// Multisets only, as order does not matter
+				start = from
+			}
+			for value := start; value < states; value++ {
+				neighbours[position] = value
+				if err := visit(position+1, value); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		counts := make([]int, states)
+		for _, value := range neighbours {
+			counts[value]++
+		}
+		for centre := 0; centre < states; centre++ {
+			output := centre
+			for _, t := range transitions {
+				if next := t.apply(centre, neighbours, orders); next >= 0 {
+					output = next
+					break
+				}
+			}
+			key := fmt.Sprint(centre, counts)
+			if previous, ok := seen[key]; ok {
+				if previous != output {
+					return fmt.Errorf("%w: state %d becomes both %d and %d depending on neighbour positions",
+						ErrNotCountBased, centre, previous, output)
+				}
+				continue
+			}
+			seen[key] = output
+			outputs = append(outputs, tableOutput{centre, counts, output})
+		}
+		return nil
+	}
+	if err := visit(0, 0); err != nil {
+		return nil, err
+	}
+	return outputs, nil
+}
+
+// countBasedRules searches for a set of alive states, never including
+// state 0 which is the boundary in Golly, that makes every output a
+// function of the centre state and its amount of alive neighbours
+func countBasedRules(states int, outputs []tableOutput) (Rules, error) {
+	for mask := 0; mask < 1<<uint(states-1); mask++ {
+		alive := make([]bool, states)
+		for state := 1; state < states; state++ {
+			alive[state] = mask&(1<<uint(state-1)) != 0
+		}
+		table := make([][9]int, states)
+		for idx := range table {
+			for count := range table[idx] {
+				table[idx][count] = -1
+			}
+		}
+		consistent := true
+		for _, o := range outputs {
+			count := 0
+			for state, n := range o.counts {
+				if alive[state] {
+					count += n
+				}
+			}
+			if table[o.centre][count] == -1 {
+				table[o.centre][count] = o.output
+			} else if table[o.centre][count] != o.output {
+				consistent = false
+				break
+			}
+		}
+		if !consistent {
+			continue
+		}
+		rs := Rules{make([]Rule, states)}
+		for idx := range rs.Array {
+			rs.Array[idx].Alive = alive[idx]
+			for count, next := range table[idx] {
+				if next == -1 {
+					next = table[idx][0]
+				}
+				rs.Array[idx].Transitions[count] = uint8(next)
+			}
+		}
+		return rs, nil
+	}
+	return Rules{}, fmt.Errorf("%w: no set of alive states explains every transition", ErrNotCountBased)
+}
+
+// TableStates is the Golly state EncodeRuleTable writes each rule as.
+// State 0 is the background in Golly so it must be dead, if rule 0 is
+// alive it swaps states with the first dead rule
+func TableStates(rs Rules) ([]uint8, error) {
+	states := make([]uint8, len(rs.Array))
+	for idx := range states {
+		states[idx] = uint8(idx)
+	}
+	if len(rs.Array) == 0 || !rs.Array[0].Alive {
+		return states, nil
+	}
+	for idx, ru := range rs.Array {
+		if !ru.Alive {
+			states[0], states[idx] = uint8(idx), 0
+			return states, nil
+		}
+	}
+	return nil, errors.New("rule tables need a rule that is not alive for the background")
+}
+
+// TableGrid maps a grid of rules to the states of the rule table
+// EncodeRuleTable writes for them, so it can be exported alongside
+func TableGrid(rs Rules, grid [][]uint8) ([][]uint8, error) {
+	states, err := TableStates(rs)
+	if err != nil {
+		return nil, err
+	}
+	mapped := make([][]uint8, len(grid))
+	for y := range grid {
+		mapped[y] = make([]uint8, len(grid[y]))
+		for x, rule := range grid[y] {
+			if int(rule) >= len(states) {
+				return nil, fmt.Errorf("cell X: %d Y: %d uses rule %d of %d", x, y, rule, len(states))
+			}
+			mapped[y][x] = states[rule]
+		}
+	}
+	return mapped, nil
+}
+
+// EncodeRuleTable writes Rules as a Golly permute rule table with colours.
+// Rules are written as the states TableStates gives them
+func EncodeRuleTable(w io.Writer, rt RuleTable) error {
+	states := len(rt.Rules.Array)
+	if states < 2 {
+		return errors.New("rule tables need at least 2 rules")
+	}
+	mapping, err := TableStates(rt.Rules)
+	if err != nil {
+		return err
+	}
+	rules := make([]Rule, states)
+	for idx, ru := range rt.Rules.Array {
+		for count, next := range ru.Transitions {
+			if int(next) >= states {
+				return fmt.Errorf("rule %d transitions to rule %d of %d", idx, next, states)
+			}
+			ru.Transitions[count] = mapping[next]
+		}
+		rules[mapping[idx]] = ru
+	}
+	name := rt.Name
+	if name == "" {
+		name = "gogol"
+	}
+	var alive, dead []string
+	for idx, ru := range rules {
+		if ru.Alive {
+			alive = append(alive, strconv.Itoa(idx))
+		} else {
+			dead = append(dead, strconv.Itoa(idx))
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "@RULE %s\n\n", name)
+	bw.WriteString("@TABLE\n")
+	fmt.Fprintf(bw, "n_states:%d\n", states)
+	bw.WriteString("neighborhood:Moore\n")
+	bw.WriteString("symmetries:permute\n\n")
+
+	// Variables are bound in Golly, so each position needs its own
+	term := func(prefix string, set []string, position int) string {
+		if len(set) == 1 {
+			return set[0]
+		}
+		return fmt.Sprintf("%s%d", prefix, position)
+	}
+	for position := 1; position <= 8; position++ {
+		if len(alive) > 1 {
+			fmt.Fprintf(bw, "var a%d={%s}\n", position, strings.Join(alive, ","))
+		}
+		if len(dead) > 1 {
+			fmt.Fprintf(bw, "var d%d={%s}\n", position, strings.Join(dead, ","))
+		}
+	}
+	bw.WriteString("\n")
+
+	for centre, ru := range rules {
+		for count, next := range ru.Transitions {
+			if int(next) == centre || (count > 0 && len(alive) == 0) || (count < 8 && len(dead) == 0) {
+				continue
+			}
+			entries := []string{strconv.Itoa(centre)}
+			for position := 1; position <= 8; position++ {
+				if position <= count {
+					entries = append(entries, term("a", alive, position))
+				} else {
+					entries = append(entries, term("d", dead, position))
+				}
+			}
+			entries = append(entries, strconv.Itoa(int(next)))
+			fmt.Fprintln(bw, strings.Join(entries, ","))
+		}
+	}
+
+	bw.WriteString("\n@COLORS\n")
+	for idx, ru := range rules {
+		fmt.Fprintf(bw, "%d %d %d %d\n", idx, channel(ru.Colour.R), channel(ru.Colour.G), channel(ru.Colour.B))
+	}
+	return bw.Flush()
+}
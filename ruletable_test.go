@@ -0,0 +1,148 @@
+package gol
+
+// Golly rule table testing
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const briansBrainTable = `@RULE BriansBrain
+
+@TABLE
+n_states:3
+neighborhood:Moore
+symmetries:permute
+
+var a={0,1,2}
+var b={a}
+var c={a}
+var d={0,2}
+var e={d}
+var f={d}
+var g={d}
+var h={d}
+var i={d}
+var j={a}
+var k={a}
+var l={a}
+var m={a}
+var n={a}
+var o={a}
+
+0,1,1,d,e,f,g,h,i,1
+1,a,b,c,j,k,l,m,n,2
+2,a,b,c,j,k,l,m,n,0
+
+@COLORS
+0 48 48 48
+1 255 255 255
+2 0 0 255
+`
+
+func TestDecodeRuleTable(t *testing.T) {
+	rt, err := DecodeRuleTable(strings.NewReader(briansBrainTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rt.Name != "BriansBrain" {
+		t.Fatalf("Rule name not read, got %q", rt.Name)
+	}
+
+	expected, _ := ParseRuleString("B2/S/C3")
+	for idx, r := range rt.Rules.Array {
+		if r.Alive != expected.Array[idx].Alive || r.Transitions != expected.Array[idx].Transitions {
+			t.Fatalf("Rule %d does not match Brian's Brain: %v", idx, r)
+		}
+	}
+
+	if rt.Rules.Array[2].Colour != (Colour{0, 0, 1}) {
+		t.Fatalf("Colours not read, got %v", rt.Rules.Array[2].Colour)
+	}
+}
+
+func TestRuleTableRoundTrip(t *testing.T) {
+	for seed := uint64(0); seed < 40; seed++ {
+		var rules Rules
+		rules.RandomizeWith(NewRand(seed), 2+int(seed%2))
+		states, err := TableStates(rules)
+		allAlive := rules.Array[0].Alive && rules.Array[1].Alive && (len(rules.Array) == 2 || rules.Array[2].Alive)
+		if allAlive {
+			if err == nil {
+				t.Fatalf("Seed %d: expected an error with no dead rule for the background", seed)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Seed %d: %v", seed, err)
+		}
+
+		var buf bytes.Buffer
+		if err := EncodeRuleTable(&buf, RuleTable{"Random", rules}); err != nil {
+			t.Fatalf("Seed %d: %v", seed, err)
+		}
+		rt, err := DecodeRuleTable(&buf)
+		if err != nil {
+			t.Fatalf("Seed %d: %v", seed, err)
+		}
+		if rt.Rules.Array[0].Alive && !allAlive {
+			t.Fatalf("Seed %d: state 0 of the table is alive", seed)
+		}
+
+		for idx, r := range rules.Array {
+			if r.Colour.NRGBA() != rt.Rules.Array[states[idx]].Colour.NRGBA() {
+				t.Fatalf("Seed %d: rule %d colour not read back", seed, idx)
+			}
+		}
+		// The alive states read back may differ where it makes no
+		// difference, so the rules are compared by running them on
+		// the same field mapped to the table's states
+		o := Options{X: 12, Y: 12, RuleNumber: len(rules.Array), Rules: rules}
+		original := MakeGame(o)
+		o.Grid, err = TableGrid(rules, original.Field.Front)
+		if err != nil {
+			t.Fatal(err)
+		}
+		o.Rules = rt.Rules
+		decoded := MakeGame(o)
+		for tick := 1; tick <= 10; tick++ {
+			original.Tick()
+			decoded.Tick()
+			mapped, _ := TableGrid(rules, original.Field.Front)
+			if mismatchCheck(mapped, decoded.Field.Front) {
+				t.Fatalf("Seed %d: decoded rules differ from the original at tick %d", seed, tick)
+			}
+		}
+	}
+}
+
+func TestRuleTableUnsupported(t *testing.T) {
+	for _, table := range []string{
+		"@RULE Tree\n@TREE\nnum_states=2\n",
+		"@RULE Hex\n@TABLE\nn_states:2\nneighborhood:hexagonal\n",
+		"@RULE Big\n@TABLE\nn_states:9\nneighborhood:Moore\nsymmetries:permute\n",
+		"@RULE Rotate\n@TABLE\nn_states:5\nneighborhood:Moore\nsymmetries:rotate4\n",
+	} {
+		if _, err := DecodeRuleTable(strings.NewReader(table)); !errors.Is(err, ErrUnsupportedTable) {
+			t.Fatalf("Expected ErrUnsupportedTable for %q, got %v", table, err)
+		}
+	}
+}
+
+func TestRuleTableNotCountBased(t *testing.T) {
+	// Birth only from a northern neighbour depends on position
+	table := `@RULE North
+@TABLE
+n_states:2
+neighborhood:Moore
+symmetries:none
+0,1,0,0,0,0,0,0,0,1
+`
+	_, err := DecodeRuleTable(strings.NewReader(table))
+	if !errors.Is(err, ErrNotCountBased) {
+		t.Fatalf("Expected ErrNotCountBased, got %v", err)
+	}
+}
@@ -1,6 +1,10 @@
 package gol
 
-import "sync"
+import (
+	"bytes"
+	"context"
+	"time"
+)
 
 // TickFunction is called to Tick a Game
 type TickFunction func(g Game, gameNumber int)
@@ -13,15 +17,239 @@ func Run(g Game, TickFunction TickFunction, gameNumber int) {
 // RunMany games of life concurrently
 // TickFunction is run on every tick of the game, so it
 // can be used to halt execution early or change the state
-func RunMany(Options Options, gameAmount int, TickFunction TickFunction) {
-	var wg sync.WaitGroup
-	wg.Add(gameAmount)
-	for i := 0; i < gameAmount; i++ {
-		go func(i int) {
-			defer wg.Done()
-			g := MakeGame(Options)
-			Run(g, TickFunction, i)
-		}(i)
-	}
-	wg.Wait()
+// Each game gets its own copy of the Options, and at most
+// one game per CPU runs at once. TickFunction is given a Copy
+// of each game, so the games in the results are as they were made
+func RunMany(Options Options, gameAmount int, TickFunction TickFunction) []GameResult {
+	return RunManyContext(context.Background(), ManyConfig{
+		Games:   gameAmount,
+		Options: Options,
+		GameFunction: func(g *Game, gameNumber int) {
+			Run(g.Copy(), TickFunction, gameNumber)
+		}})
+}
+
+// StopReason explains why RunContext stopped
+type StopReason int
+
+// Reasons a run can stop
+const (
+	StopMaxTicks StopReason = iota
+	StopBudget
+	StopCancelled
+	StopExtinct
+	StopStatic
+	StopPeriodic
+	StopPopulation
+	StopPredicate
+	StopPanicked
+)
+
+func (sr StopReason) String() string {
+	switch sr {
+	case StopMaxTicks:
+		return "max ticks"
+	case StopBudget:
+		return "budget"
+	case StopCancelled:
+		return "cancelled"
+	case StopExtinct:
+		return "extinct"
+	case StopStatic:
+		return "static"
+	case StopPeriodic:
+		return "periodic"
+	case StopPopulation:
+		return "population"
+	case StopPredicate:
+		return "predicate"
+	case StopPanicked:
+		return "panicked"
+	}
+	return "unknown"
+}
+
+// Predicate is a custom stop condition checked after every Tick
+type Predicate struct {
+	Name string
+	Stop func(g *Game) bool
+}
+
+// RunConfig configures when RunContext stops,
+// zero values disable a condition
+type RunConfig struct {
+	// MaxTicks to run for
+	MaxTicks int
+	// Budget of wall-clock time for the run
+	Budget time.Duration
+	// StopOnExtinction when no cells are alive
+	StopOnExtinction bool
+	// StopOnStatic when a Tick changes nothing
+	StopOnStatic bool
+	// MaxPeriod stops when the field repeats a state
+	// from at most this many ticks ago
+	MaxPeriod int
+	// MinPopulation and MaxPopulation stop when the alive
+	// count leaves these bounds
+	MinPopulation, MaxPopulation int
+	// Predicates are custom stop conditions
+	Predicates []Predicate
+}
+
+// RunResult describes why and when a run stopped
+type RunResult struct {
+	Reason StopReason
+	// Ticks performed during this run
+	Ticks int
+	// Tick counter of the game when it stopped
+	Tick       int
+	Elapsed    time.Duration
+	Population int
+	// Period of the repeated state for static and periodic stops
+	Period int
+	// Predicate is the name of the custom predicate that stopped the run
+	Predicate string
+	// Err is the context error for cancelled runs
+	Err error
+}
+
+// hashGrid hashes the states compared by RunContext, a variable so
+// tests can force collisions
+var hashGrid = HashGrid
+
+// pastState is a state RunContext may see repeat, the grid is kept
+// as well as the hash so a hash collision is never taken as a repeat
+type pastState struct {
+	hash uint64
+	grid [][]uint8
+}
+
+// same is true when a grid is the state, checking the hash first
+func (ps pastState) same(hash uint64, grid [][]uint8) bool {
+	if ps.hash != hash {
+		return false
+	}
+	for y := range grid {
+		if !bytes.Equal(ps.grid[y], grid[y]) {
+			return false
+		}
+	}
+	return true
+}
+
+// record copies a grid into a past state, reusing its grid
+func (ps *pastState) record(hash uint64, grid [][]uint8) {
+	ps.hash = hash
+	if len(ps.grid) != len(grid) {
+		ps.grid = make([][]uint8, len(grid))
+	}
+	for y := range grid {
+		ps.grid[y] = append(ps.grid[y][:0], grid[y]...)
+	}
+}
+
+// RunContext Ticks a Game until one of the configured conditions
+// is met or the context is done. A config with no conditions
+// runs until the context is cancelled
+func RunContext(ctx context.Context, g *Game, c RunConfig) RunResult {
+	start := time.Now()
+	startTick := g.ticks
+	var deadline <-chan time.Time
+	if c.Budget > 0 {
+		timer := time.NewTimer(c.Budget)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	trackPeriod := c.StopOnStatic || c.MaxPeriod > 0
+	historyLength := c.MaxPeriod
+	if historyLength < 1 {
+		historyLength = 1
+	}
+	var history []pastState
+	if trackPeriod {
+		history = make([]pastState, 1, historyLength+1)
+		history[0].record(hashGrid(g.Field.Front), g.Field.Front)
+	}
+
+	result := func(reason StopReason) RunResult {
+		return RunResult{
+			Reason:     reason,
+			Ticks:      g.ticks - startTick,
+			Tick:       g.ticks,
+			Elapsed:    time.Since(start),
+			Population: g.Population()}
+	}
+
+	for {
+		if reason, name, ok := checkStop(g, c); ok {
+			r := result(reason)
+			r.Predicate = name
+			return r
+		}
+
+		if c.MaxTicks > 0 && g.ticks-startTick >= c.MaxTicks {
+			return result(StopMaxTicks)
+		}
+
+		select {
+		case <-ctx.Done():
+			r := result(StopCancelled)
+			r.Err = ctx.Err()
+			return r
+		case <-deadline:
+			return result(StopBudget)
+		default:
+		}
+
+		g.Tick()
+
+		if trackPeriod {
+			hash := hashGrid(g.Field.Front)
+			for age := 1; age <= len(history); age++ {
+				if !history[len(history)-age].same(hash, g.Field.Front) {
+					continue
+				}
+				if age == 1 && c.StopOnStatic {
+					r := result(StopStatic)
+					r.Period = 1
+					return r
+				}
+				if age <= c.MaxPeriod {
+					r := result(StopPeriodic)
+					r.Period = age
+					return r
+				}
+			}
+			// The oldest state's grid is reused once it is forgotten
+			var next pastState
+			if len(history) == historyLength {
+				next = history[0]
+				copy(history, history[1:])
+				history = history[:len(history)-1]
+			}
+			next.record(hash, g.Field.Front)
+			history = append(history, next)
+		}
+	}
+}
+
+// checkStop tests the population and custom conditions,
+// returning the reason and predicate name if the run should stop
+func checkStop(g *Game, c RunConfig) (StopReason, string, bool) {
+	if c.StopOnExtinction || c.MinPopulation > 0 || c.MaxPopulation > 0 {
+		population := g.Population()
+		if c.StopOnExtinction && population == 0 {
+			return StopExtinct, "", true
+		}
+		if population < c.MinPopulation || (c.MaxPopulation > 0 && population > c.MaxPopulation) {
+			return StopPopulation, "", true
+		}
+	}
+	for _, p := range c.Predicates {
+		if p.Stop(g) {
+			return StopPredicate, p.Name, true
+		}
+	}
+	return 0, "", false
 }
@@ -0,0 +1,111 @@
+package gol
+
+// RunContext testing
+
+// Checking that each stop condition halts a run when expected
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRunExtinction(t *testing.T) {
+	y0 := []uint8{0, 0, 0}
+	y1 := []uint8{0, 1, 0}
+	y2 := []uint8{0, 0, 0}
+	g := MakeGame(Options{3, 3, [][]uint8{y0, y1, y2}, 2, rs})
+
+	r := RunContext(context.Background(), &g, RunConfig{MaxTicks: 10, StopOnExtinction: true})
+
+	if r.Reason != StopExtinct || r.Ticks != 1 {
+		t.Fatalf("Expected extinction after 1 tick, got %s after %d", r.Reason, r.Ticks)
+	}
+}
+
+func TestRunStatic(t *testing.T) {
+	y0 := []uint8{0, 0, 0, 0}
+	y1 := []uint8{0, 1, 1, 0}
+	y2 := []uint8{0, 1, 1, 0}
+	y3 := []uint8{0, 0, 0, 0}
+	g := MakeGame(Options{4, 4, [][]uint8{y0, y1, y2, y3}, 2, rs})
+
+	r := RunContext(context.Background(), &g, RunConfig{MaxTicks: 10, StopOnStatic: true})
+
+	if r.Reason != StopStatic || r.Period != 1 || r.Population != 4 {
+		t.Fatalf("Expected a static square, got %s period %d population %d", r.Reason, r.Period, r.Population)
+	}
+}
+
+func TestRunPeriodic(t *testing.T) {
+	y0 := []uint8{0, 0, 0, 0, 0}
+	y1 := []uint8{0, 0, 1, 0, 0}
+	y2 := []uint8{0, 0, 1, 0, 0}
+	y3 := []uint8{0, 0, 1, 0, 0}
+	y4 := []uint8{0, 0, 0, 0, 0}
+	g := MakeGame(Options{5, 5, [][]uint8{y0, y1, y2, y3, y4}, 2, rs})
+
+	r := RunContext(context.Background(), &g, RunConfig{MaxTicks: 10, StopOnStatic: true, MaxPeriod: 4})
+
+	if r.Reason != StopPeriodic || r.Period != 2 || r.Ticks != 2 {
+		t.Fatalf("Expected a period 2 blinker, got %s period %d after %d", r.Reason, r.Period, r.Ticks)
+	}
+}
+
+func TestRunHashCollision(t *testing.T) {
+	defer func(h func([][]uint8) uint64) { hashGrid = h }(hashGrid)
+	hashGrid = func([][]uint8) uint64 { return 0 }
+
+	// Every state hashes the same, so only the grids tell them apart
+	y0 := []uint8{0, 0, 0, 0, 0, 0}
+	y1 := []uint8{0, 0, 1, 1, 0, 0}
+	y2 := []uint8{0, 1, 1, 0, 0, 0}
+	y3 := []uint8{0, 0, 1, 0, 0, 0}
+	y4 := []uint8{0, 0, 0, 0, 0, 0}
+	y5 := []uint8{0, 0, 0, 0, 0, 0}
+	g := MakeGame(Options{6, 6, [][]uint8{y0, y1, y2, y3, y4, y5}, 2, rs})
+
+	r := RunContext(context.Background(), &g, RunConfig{MaxTicks: 3, StopOnStatic: true, MaxPeriod: 2})
+	if r.Reason != StopMaxTicks {
+		t.Fatalf("Expected colliding hashes to be ignored, got %s period %d", r.Reason, r.Period)
+	}
+
+	y0 = []uint8{0, 0, 0, 0, 0}
+	y1 = []uint8{0, 0, 1, 0, 0}
+	y2 = []uint8{0, 0, 1, 0, 0}
+	y3 = []uint8{0, 0, 1, 0, 0}
+	y4 = []uint8{0, 0, 0, 0, 0}
+	g = MakeGame(Options{5, 5, [][]uint8{y0, y1, y2, y3, y4}, 2, rs})
+
+	r = RunContext(context.Background(), &g, RunConfig{MaxTicks: 10, StopOnStatic: true, MaxPeriod: 4})
+	if r.Reason != StopPeriodic || r.Period != 2 {
+		t.Fatalf("Expected a period 2 blinker despite collisions, got %s period %d", r.Reason, r.Period)
+	}
+}
+
+func TestRunMaxTicksAndPredicate(t *testing.T) {
+	g := MakeGame(Options{10, 10, [][]uint8{}, 2, rs})
+
+	r := RunContext(context.Background(), &g, RunConfig{MaxTicks: 5})
+	if r.Reason != StopMaxTicks || r.Ticks != 5 || g.Ticks() != 5 {
+		t.Fatalf("Expected 5 ticks, got %s after %d", r.Reason, r.Ticks)
+	}
+
+	r = RunContext(context.Background(), &g, RunConfig{Predicates: []Predicate{
+		{"eight", func(g *Game) bool { return g.Ticks() == 8 }}}})
+	if r.Reason != StopPredicate || r.Predicate != "eight" || r.Tick != 8 || r.Ticks != 3 {
+		t.Fatalf("Expected predicate stop at tick 8, got %s at %d", r.Reason, r.Tick)
+	}
+}
+
+func TestRunCancel(t *testing.T) {
+	g := MakeGame(Options{10, 10, [][]uint8{}, 2, rs})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	r := RunContext(ctx, &g, RunConfig{})
+
+	if r.Reason != StopCancelled || r.Err != context.DeadlineExceeded {
+		t.Fatalf("Expected cancellation, got %s %v", r.Reason, r.Err)
+	}
+}
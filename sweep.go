@@ -0,0 +1,332 @@
+package gol
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SweepProtocol is the fixed setup every rule set in a sweep is run with
+type SweepProtocol struct {
+	X, Y       int
+	RuleNumber int
+	// Seed for the initial grid, shared by every entry
+	Seed int64
+	// Ticks each entry runs for at most, must be positive
+	Ticks int
+}
+
+// SweepConfig configures a rule-space sweep
+type SweepConfig struct {
+	Protocol SweepProtocol
+	// Entries is the amount of rule sets to run
+	Entries int
+	// Enumerate rule sets with RulesFromIndex starting at Start,
+	// otherwise rule sets are sampled randomly from RuleSeed.
+	// Enumerated entries must all be within RuleSpaceSize
+	Enumerate bool
+	Start     uint64
+	RuleSeed  int64
+	// Workers running entries at once, defaults to the number of CPUs
+	Workers int
+	// Results file appended to, CSV if it ends in .csv otherwise JSONL
+	Results string
+	// SaveDir is where the initial state of each entry is saved, if set
+	SaveDir string
+	// ThumbnailDir is where a PNG of the final state of each entry is saved, if set
+	ThumbnailDir string
+	Thumbnail    ImageOptions
+	// Run adds extra stop conditions, MaxTicks is set from the Protocol
+	Run RunConfig
+}
+
+// SweepRecord is the metrics of a single sweep entry
+type SweepRecord struct {
+	Entry          int     `json:"entry"`
+	Rules          []Rule  `json:"rules"`
+	Reason         string  `json:"reason"`
+	Ticks          int     `json:"ticks"`
+	Period         int     `json:"period"`
+	Population     int     `json:"population"`
+	RulePopulation []int   `json:"rulePopulation"`
+	Seconds        float64 `json:"seconds"`
+	Error          string  `json:"error,omitempty"`
+}
+
+var sweepHeader = []string{"entry", "rules", "reason", "ticks", "period", "population", "rulePopulation", "seconds", "error"}
+
+// Sweep runs every entry of a sweep that is not already in the results
+// file, appending a record as each finishes so an interrupted sweep can
+// be resumed by running it again with the same config
+func Sweep(ctx context.Context, c SweepConfig) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	isCSV := strings.EqualFold(filepath.Ext(c.Results), ".csv")
+
+	done, err := sweepCompleted(c.Results, isCSV)
+	if err != nil {
+		return err
+	}
+	pending := []int{}
+	for entry := 0; entry < c.Entries; entry++ {
+		if !done[entry] {
+			pending = append(pending, entry)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	f, err := openResults(c.Results, isCSV)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	for _, dir := range []string{c.SaveDir, c.ThumbnailDir} {
+		if dir == "" {
+			continue
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	// Returning early cancels the pool and waits for it to finish
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	run := c.Run
+	run.MaxTicks = c.Protocol.Ticks
+	results := RunManyStream(ctx, ManyConfig{
+		Games:   len(pending),
+		Workers: c.Workers,
+		OptionsFunc: func(i int) Options {
+			return c.Options(pending[i])
+		},
+		Run: run})
+	fail := func(err error) error {
+		cancel()
+		for range results {
+		}
+		return err
+	}
+
+	for result := range results {
+		if result.Run.Reason == StopCancelled {
+			continue
+		}
+		entry := pending[result.Index]
+		record := SweepRecord{
+			Entry:          entry,
+			Rules:          result.Game.Rules.Array,
+			Reason:         result.Run.Reason.String(),
+			Ticks:          result.Run.Ticks,
+			Period:         result.Run.Period,
+			Population:     result.Run.Population,
+			RulePopulation: result.Game.RulePopulation(),
+			Seconds:        result.Run.Elapsed.Seconds()}
+		if result.Err != nil {
+			record.Error = result.Err.Error()
+			record.RulePopulation = nil
+		}
+		if result.Err == nil && c.ThumbnailDir != "" {
+			err := SavePNG(&result.Game, filepath.Join(c.ThumbnailDir, fmt.Sprintf("%08d.png", entry)), c.Thumbnail)
+			if err != nil {
+				return fail(err)
+			}
+		}
+		if result.Err == nil && c.SaveDir != "" {
+			o := c.Options(entry)
+			// The protocol seed is for the grid, not the game's random generator
+			err := Save(SaveContent{
+				Name:        fmt.Sprintf("sweep entry %d", entry),
+				Description: fmt.Sprintf("grid seed %d", c.Protocol.Seed),
+				Rules:       o.Rules.Array,
+				Grid:        o.Grid},
+				filepath.Join(c.SaveDir, fmt.Sprintf("%08d.json", entry)))
+			if err != nil {
+				return fail(err)
+			}
+		}
+		if err := writeRecord(f, record, isCSV); err != nil {
+			return fail(err)
+		}
+	}
+	return ctx.Err()
+}
+
+// validate checks a sweep has a size, a rule number and a tick limit,
+// and that enumerated entries neither run past the end of the rule
+// space nor wrap
+func (c SweepConfig) validate() error {
+	if c.Protocol.X <= 0 || c.Protocol.Y <= 0 {
+		return fmt.Errorf("sweep games must have a size, got %dx%d", c.Protocol.X, c.Protocol.Y)
+	}
+	if c.Protocol.RuleNumber < 1 || c.Protocol.RuleNumber > 256 {
+		return fmt.Errorf("sweep rule number must be from 1 to 256, got %d", c.Protocol.RuleNumber)
+	}
+	if c.Protocol.Ticks <= 0 {
+		return fmt.Errorf("sweep ticks must be positive, got %d", c.Protocol.Ticks)
+	}
+	if c.Entries < 0 {
+		return fmt.Errorf("sweep entries cannot be negative, got %d", c.Entries)
+	}
+	if !c.Enumerate {
+		return nil
+	}
+	end := c.Start + uint64(c.Entries)
+	if end < c.Start {
+		return fmt.Errorf("sweep of %d entries from %d overflows", c.Entries, c.Start)
+	}
+	if size, ok := RuleSpaceSize(c.Protocol.RuleNumber); ok && end > size {
+		return fmt.Errorf("sweep of %d entries from %d passes the %d rule sets of %d rules",
+			c.Entries, c.Start, size, c.Protocol.RuleNumber)
+	}
+	return nil
+}
+
+// Options returns the Options of a sweep entry, the same
+// entry always produces the same Options
+func (c SweepConfig) Options(entry int) Options {
+	var rules Rules
+	if c.Enumerate {
+		rules = RulesFromIndex(c.Protocol.RuleNumber, c.Start+uint64(entry))
+	} else {
+		rules.RandomizeWith(rand.New(rand.NewSource(c.RuleSeed+int64(entry))), c.Protocol.RuleNumber)
+	}
+	field := MakeGridBuffers(c.Protocol.X, c.Protocol.Y, false)
+	field.RandomizeWith(rand.New(rand.NewSource(c.Protocol.Seed)), c.Protocol.RuleNumber)
+	return Options{
+		X:          c.Protocol.X,
+		Y:          c.Protocol.Y,
+		Grid:       field.Front,
+		RuleNumber: c.Protocol.RuleNumber,
+		Rules:      rules}
+}
+
+// sweepCompleted reads the entries already in a results file,
+// skipping any record left incomplete by a crash
+func sweepCompleted(filename string, isCSV bool) (map[int]bool, error) {
+	done := map[int]bool{}
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return done, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if isCSV {
+		reader := csv.NewReader(f)
+		reader.FieldsPerRecord = -1
+		for {
+			row, err := reader.Read()
+			if err == io.EOF {
+				break
+			} else if _, ok := err.(*csv.ParseError); ok {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			if len(row) != len(sweepHeader) {
+				continue
+			}
+			if entry, err := strconv.Atoi(row[0]); err == nil {
+				done[entry] = true
+			}
+		}
+		return done, nil
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16*1024*1024)
+	for scanner.Scan() {
+		var record SweepRecord
+		if json.Unmarshal(scanner.Bytes(), &record) == nil {
+			done[record.Entry] = true
+		}
+	}
+	return done, scanner.Err()
+}
+
+// openResults opens a results file for appending, terminating any
+// partially written last line and writing a CSV header if needed
+func openResults(filename string, isCSV bool) (*os.File, error) {
+	f, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if info.Size() == 0 {
+		if isCSV {
+			err = writeCSV(f, sweepHeader)
+		}
+	} else {
+		last := make([]byte, 1)
+		if _, err = f.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			_, err = f.Write([]byte("\n"))
+		}
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// writeRecord writes a whole record with a single write so
+// a crash leaves at most one incomplete line
+func writeRecord(w io.Writer, record SweepRecord, isCSV bool) error {
+	rules, err := json.Marshal(record.Rules)
+	if err != nil {
+		return err
+	}
+	if !isCSV {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(line, '\n'))
+		return err
+	}
+	populations := make([]string, len(record.RulePopulation))
+	for idx, p := range record.RulePopulation {
+		populations[idx] = strconv.Itoa(p)
+	}
+	return writeCSV(w, []string{
+		strconv.Itoa(record.Entry),
+		string(rules),
+		record.Reason,
+		strconv.Itoa(record.Ticks),
+		strconv.Itoa(record.Period),
+		strconv.Itoa(record.Population),
+		strings.Join(populations, " "),
+		strconv.FormatFloat(record.Seconds, 'f', -1, 64),
+		record.Error})
+}
+
+func writeCSV(w io.Writer, row []string) error {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write(row)
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
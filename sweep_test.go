@@ -0,0 +1,183 @@
+package gol
+
+// Sweep testing
+
+// Checking that sweeps are repeatable and resume where they stopped
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func sweepEntries(t *testing.T, filename string) map[int]int {
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	entries := map[int]int{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record SweepRecord
+		if json.Unmarshal(scanner.Bytes(), &record) == nil {
+			entries[record.Entry]++
+		}
+	}
+	return entries
+}
+
+func TestSweepResume(t *testing.T) {
+	dir := t.TempDir()
+	c := SweepConfig{
+		Protocol: SweepProtocol{X: 8, Y: 8, RuleNumber: 3, Seed: 1, Ticks: 10},
+		Entries:  6,
+		RuleSeed: 7,
+		Workers:  2,
+		Results:  filepath.Join(dir, "results.jsonl")}
+
+	if err := Sweep(context.Background(), c); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a crash part way through writing the last record
+	data, err := os.ReadFile(c.Results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(c.Results, data[:len(data)-10], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Entries = 8
+	if err := Sweep(context.Background(), c); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := sweepEntries(t, c.Results)
+	for entry := 0; entry < 8; entry++ {
+		if entries[entry] != 1 {
+			t.Fatalf("Entry %d recorded %d times", entry, entries[entry])
+		}
+	}
+}
+
+func TestSweepValidate(t *testing.T) {
+	size, _ := RuleSpaceSize(2)
+	for _, c := range []SweepConfig{
+		{Protocol: SweepProtocol{X: 4, Y: 4, RuleNumber: 2}, Entries: 1},
+		{Protocol: SweepProtocol{X: 4, Y: 4, RuleNumber: 2, Ticks: 1}, Entries: -1},
+		{Protocol: SweepProtocol{X: 4, Y: 4, RuleNumber: 2, Ticks: 1}, Entries: 2, Enumerate: true, Start: size - 1},
+		{Protocol: SweepProtocol{X: 4, Y: 4, RuleNumber: 30, Ticks: 1}, Entries: 2, Enumerate: true, Start: ^uint64(0)},
+		{Protocol: SweepProtocol{Y: 4, RuleNumber: 2, Ticks: 1}, Entries: 1},
+		{Protocol: SweepProtocol{X: 4, RuleNumber: 2, Ticks: 1}, Entries: 1},
+		{Protocol: SweepProtocol{X: 4, Y: 4, Ticks: 1}, Entries: 1},
+		{Protocol: SweepProtocol{X: 4, Y: 4, RuleNumber: 257, Ticks: 1}, Entries: 1},
+	} {
+		c.Results = filepath.Join(t.TempDir(), "results.jsonl")
+		if err := Sweep(context.Background(), c); err == nil {
+			t.Fatalf("Expected an error for %+v", c)
+		}
+	}
+
+	c := SweepConfig{
+		Protocol:  SweepProtocol{X: 4, Y: 4, RuleNumber: 2, Ticks: 1},
+		Entries:   2,
+		Enumerate: true,
+		Start:     size - 2,
+		Results:   filepath.Join(t.TempDir(), "results.jsonl")}
+	if err := Sweep(context.Background(), c); err != nil {
+		t.Fatalf("Expected the last rule sets to sweep, got %v", err)
+	}
+}
+
+func TestSweepErrorStopsPool(t *testing.T) {
+	dir := t.TempDir()
+	c := SweepConfig{
+		Protocol:     SweepProtocol{X: 8, Y: 8, RuleNumber: 2, Seed: 1, Ticks: 5},
+		Entries:      40,
+		Workers:      4,
+		Results:      filepath.Join(dir, "results.jsonl"),
+		ThumbnailDir: filepath.Join(dir, "thumbnails")}
+	// Directories in the way of every thumbnail make each save fail
+	for entry := 0; entry < c.Entries; entry++ {
+		if err := os.MkdirAll(filepath.Join(c.ThumbnailDir, fmt.Sprintf("%08d.png", entry)), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	before := runtime.NumGoroutine()
+	if err := Sweep(context.Background(), c); err == nil {
+		t.Fatalf("Expected the thumbnail error")
+	}
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d goroutines after the error, got %d", before, runtime.NumGoroutine())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSweepSaves(t *testing.T) {
+	dir := t.TempDir()
+	c := SweepConfig{
+		Protocol: SweepProtocol{X: 6, Y: 6, RuleNumber: 2, Seed: 5, Ticks: 3},
+		Entries:  1,
+		Results:  filepath.Join(dir, "results.jsonl"),
+		SaveDir:  filepath.Join(dir, "saves")}
+	if err := Sweep(context.Background(), c); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := LoadFile(filepath.Join(c.SaveDir, "00000000.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The grid seed is not the seed of the game's random generator
+	if s.Seed != 0 || mismatchCheck(c.Options(0).Grid, s.Grid) {
+		t.Fatalf("Expected the entry's grid saved without a generator seed, got seed %d", s.Seed)
+	}
+}
+
+func TestSweepRepeatable(t *testing.T) {
+	c := SweepConfig{
+		Protocol:  SweepProtocol{X: 5, Y: 5, RuleNumber: 2, Seed: 3, Ticks: 10},
+		Enumerate: true,
+		Start:     1000}
+
+	a, b := c.Options(4), c.Options(4)
+	if mismatchCheck(a.Grid, b.Grid) {
+		t.Fatalf("Entry grids differ between calls")
+	}
+	for idx := range a.Rules.Array {
+		if a.Rules.Array[idx] != b.Rules.Array[idx] {
+			t.Fatalf("Entry rules differ between calls")
+		}
+	}
+}
+
+func TestRulesFromIndex(t *testing.T) {
+	size, ok := RuleSpaceSize(2)
+	if !ok || size != 1<<20 {
+		t.Fatalf("Expected 2 rules to have 2^20 rule sets, got %d", size)
+	}
+
+	rules := RulesFromIndex(2, 1|1<<2|1<<11)
+	if !rules.Array[0].Alive || rules.Array[1].Alive {
+		t.Fatalf("Alive states not decoded from index")
+	}
+	if rules.Array[0].Transitions[0] != 1 || rules.Array[1].Transitions[0] != 1 {
+		t.Fatalf("Transitions not decoded from index: %v", rules.Array)
+	}
+
+	if _, ok := RuleSpaceSize(6); ok {
+		t.Fatalf("6 rules should not fit in a uint64")
+	}
+}